@@ -0,0 +1,296 @@
+// Command idlgen generates Go structs, discriminators and named account layouts from an Anchor IDL.
+//
+//	//go:generate go run ../cmd/idlgen -idl idls/pump_amm.json -pkg solanaswapgo -prefix PumpAmm -out pumpamm_idl_gen.go
+//
+// For every instruction it emits a <Prefix><Name>Discriminator, a <Prefix><Name>Args struct that borsh
+// decodes the arguments, and a <Prefix><Name>Accounts struct with a constructor naming the accounts of the
+// instruction by their position in the IDL. The constructor rejects instructions with fewer accounts than the
+// IDL lists. Regenerating from an updated IDL moves the indices with the accounts, and code using an account
+// the IDL renamed or removed stops compiling. Events get a discriminator and a struct, defined types a struct,
+// an enum or an alias.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lonelybeanz/solanaswap-go/idl"
+)
+
+func main() {
+	idlPath := flag.String("idl", "", "path of the Anchor IDL JSON file")
+	pkg := flag.String("pkg", "", "package of the generated file")
+	prefix := flag.String("prefix", "", "prefix of the generated names")
+	out := flag.String("out", "", "output file, stdout when empty")
+	instructions := flag.String("instructions", "", "comma separated instructions to generate, all when empty")
+	events := flag.String("events", "", "comma separated events to generate, all when empty")
+	flag.Parse()
+
+	if *idlPath == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*idlPath)
+	if err != nil {
+		log.Fatalf("failed to read IDL: %s", err)
+	}
+	parsed, err := idl.Parse(data)
+	if err != nil {
+		log.Fatalf("failed to parse IDL %s: %s", *idlPath, err)
+	}
+
+	g := &generator{idl: parsed, prefix: *prefix, types: make(map[string]bool)}
+	source, err := g.generate(*pkg, filepath.ToSlash(*idlPath), split(*instructions), split(*events))
+	if err != nil {
+		log.Fatalf("failed to generate %s: %s", *idlPath, err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(source)
+		return
+	}
+	if err := os.WriteFile(*out, source, 0o644); err != nil {
+		log.Fatalf("failed to write %s: %s", *out, err)
+	}
+}
+
+func split(list string) map[string]bool {
+	if list == "" {
+		return nil
+	}
+	names := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		names[strings.TrimSpace(name)] = true
+	}
+	return names
+}
+
+type generator struct {
+	idl    *idl.IDL
+	prefix string
+	buf    bytes.Buffer
+	types  map[string]bool // defined types referenced by the generated structs
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) generate(pkg string, source string, instructions map[string]bool, events map[string]bool) ([]byte, error) {
+	var body generator
+	body.idl, body.prefix, body.types = g.idl, g.prefix, g.types
+
+	for _, instruction := range g.idl.Instructions {
+		if instructions != nil && !instructions[instruction.Name] {
+			continue
+		}
+		if err := body.instruction(instruction); err != nil {
+			return nil, fmt.Errorf("instruction %s: %w", instruction.Name, err)
+		}
+	}
+	for _, event := range g.idl.Events {
+		if events != nil && !events[event.Name] {
+			continue
+		}
+		if err := body.event(event); err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.Name, err)
+		}
+	}
+	if err := body.definedTypes(); err != nil {
+		return nil, err
+	}
+
+	g.printf("// Code generated by idlgen from %s. DO NOT EDIT.\n\n", source)
+	g.printf("package %s\n\n", pkg)
+	var imports []string
+	if bytes.Contains(body.buf.Bytes(), []byte("ag_binary.")) {
+		imports = append(imports, `ag_binary "github.com/gagliardetto/binary"`)
+	}
+	if bytes.Contains(body.buf.Bytes(), []byte("solana.")) {
+		imports = append(imports, `"github.com/gagliardetto/solana-go"`)
+	}
+	if len(imports) > 0 {
+		g.printf("import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	g.buf.Write(body.buf.Bytes())
+
+	formatted, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go: %w\n%s", err, g.buf.Bytes())
+	}
+	return formatted, nil
+}
+
+func (g *generator) instruction(instruction idl.Instruction) error {
+	name := g.prefix + exportedName(instruction.Name)
+
+	g.printf("// %sDiscriminator prefixes the data of the %s instruction\n", name, instruction.Name)
+	g.printf("var %sDiscriminator = %s\n\n", name, byteArray(instruction.Discriminator))
+
+	g.printf("// %sArgs are the arguments of the %s instruction\n", name, instruction.Name)
+	if err := g.structType(name+"Args", instruction.Args); err != nil {
+		return err
+	}
+
+	g.printf("// %sAccounts are the accounts of the %s instruction\n", name, instruction.Name)
+	g.printf("type %sAccounts struct {\n", name)
+	for _, account := range instruction.Accounts {
+		g.printf("%s solana.PublicKey\n", exportedName(account.Name))
+	}
+	g.printf("}\n\n")
+	g.printf("// %sAccountsLen is the number of accounts of the %s instruction\n", name, instruction.Name)
+	g.printf("const %sAccountsLen = %d\n\n", name, len(instruction.Accounts))
+	g.printf("// New%sAccounts names the accounts of a %s instruction, ok is false when some are missing\n", name, instruction.Name)
+	g.printf("func New%sAccounts(keys []solana.PublicKey) (accounts *%sAccounts, ok bool) {\n", name, name)
+	g.printf("if len(keys) < %sAccountsLen {\nreturn nil, false\n}\n", name)
+	g.printf("return &%sAccounts{\n", name)
+	for i, account := range instruction.Accounts {
+		g.printf("%s: keys[%d],\n", exportedName(account.Name), i)
+	}
+	g.printf("}, true\n}\n\n")
+	return nil
+}
+
+func (g *generator) event(event idl.Event) error {
+	name := g.prefix + exportedName(event.Name)
+	g.printf("// %sDiscriminator prefixes the data of the %s event\n", name, event.Name)
+	g.printf("var %sDiscriminator = %s\n\n", name, byteArray(event.Discriminator))
+	g.printf("// %s is the %s event\n", name, event.Name)
+	return g.structType(name, event.Fields)
+}
+
+// definedTypes emits the defined types referenced so far, and those they reference in turn
+func (g *generator) definedTypes() error {
+	emitted := make(map[string]bool)
+	for {
+		var pending []string
+		for name := range g.types {
+			if !emitted[name] {
+				pending = append(pending, name)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		sort.Strings(pending)
+		for _, name := range pending {
+			emitted[name] = true
+			if err := g.definedType(name); err != nil {
+				return fmt.Errorf("type %s: %w", name, err)
+			}
+		}
+	}
+}
+
+func (g *generator) definedType(name string) error {
+	for _, typeDef := range g.idl.Types {
+		if typeDef.Name != name {
+			continue
+		}
+		goName := g.prefix + exportedName(name)
+		if typeDef.Alias != nil {
+			goType, err := g.goType(*typeDef.Alias)
+			if err != nil {
+				return err
+			}
+			g.printf("// %s is the %s type\n", goName, name)
+			g.printf("type %s = %s\n\n", goName, goType)
+			return nil
+		}
+		if typeDef.Kind == "struct" {
+			if typeDef.Tuple != nil {
+				return fmt.Errorf("tuple structs are not supported")
+			}
+			g.printf("// %s is the %s type\n", goName, name)
+			return g.structType(goName, typeDef.Fields)
+		}
+		for _, variant := range typeDef.Variants {
+			if variant.Fields != nil || variant.Tuple != nil {
+				return fmt.Errorf("enums with data are not supported")
+			}
+		}
+		g.printf("// %s is the %s enum\n", goName, name)
+		g.printf("type %s uint8\n\nconst (\n", goName)
+		for i, variant := range typeDef.Variants {
+			g.printf("%s%s %s = %d\n", goName, exportedName(variant.Name), goName, i)
+		}
+		g.printf(")\n\n")
+		return nil
+	}
+	return fmt.Errorf("undefined")
+}
+
+func (g *generator) structType(name string, fields []idl.Field) error {
+	g.printf("type %s struct {\n", name)
+	for _, field := range fields {
+		goType, err := g.goType(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		tag := ""
+		if field.Type.Option != nil {
+			tag = " `bin:\"optional\"`"
+		}
+		g.printf("%s %s%s\n", exportedName(field.Name), goType, tag)
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+func (g *generator) goType(t idl.Type) (string, error) {
+	switch {
+	case t.Vec != nil:
+		inner, err := g.goType(*t.Vec)
+		return "[]" + inner, err
+	case t.Option != nil:
+		inner, err := g.goType(*t.Option)
+		return "*" + inner, err
+	case t.COption != nil:
+		return "", fmt.Errorf("coption is not supported")
+	case t.Array != nil:
+		inner, err := g.goType(*t.Array)
+		return fmt.Sprintf("[%d]%s", t.ArrayLen, inner), err
+	case t.Defined != "":
+		g.types[t.Defined] = true
+		return g.prefix + exportedName(t.Defined), nil
+	}
+
+	goTypes := map[string]string{
+		"bool": "bool", "u8": "uint8", "i8": "int8", "u16": "uint16", "i16": "int16", "u32": "uint32", "i32": "int32",
+		"u64": "uint64", "i64": "int64", "f32": "float32", "f64": "float64", "u128": "ag_binary.Uint128",
+		"i128": "ag_binary.Int128", "pubkey": "solana.PublicKey", "string": "string", "bytes": "[]byte",
+	}
+	goType, ok := goTypes[t.Primitive]
+	if !ok {
+		return "", fmt.Errorf("unsupported type %q", t.Primitive)
+	}
+	return goType, nil
+}
+
+// exportedName converts snake_case and camelCase IDL names to exported Go names
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func byteArray(data []byte) string {
+	values := make([]string, len(data))
+	for i, v := range data {
+		values[i] = fmt.Sprint(v)
+	}
+	return fmt.Sprintf("[%d]byte{%s}", len(data), strings.Join(values, ", "))
+}
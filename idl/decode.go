@@ -0,0 +1,329 @@
+package idl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// eventIxTag prefixes the data of anchor self-CPI event instructions (emit_cpi!)
+var eventIxTag = []byte{228, 69, 165, 46, 81, 203, 154, 29}
+
+// DecodedInstruction is an instruction decoded with an IDL, Args maps argument names to values
+type DecodedInstruction struct {
+	Name     string
+	Args     map[string]interface{}
+	Accounts map[string]solana.PublicKey
+	Extra    []byte // bytes after the arguments known to the IDL
+}
+
+// DecodedEvent is an event decoded with an IDL, Fields maps field names to values
+type DecodedEvent struct {
+	Name   string
+	Fields map[string]interface{}
+	Extra  []byte
+}
+
+// DecodedAccount is an account decoded with an IDL
+type DecodedAccount struct {
+	Name   string
+	Fields map[string]interface{}
+	Extra  []byte
+}
+
+// DecodeInstruction decodes instruction data by discriminator, accounts are named after the IDL
+// accounts when given. Values are uint64, int64 and so on for integers, *big.Int for 128 bit
+// integers, solana.PublicKey, string, []byte for bytes and u8 vecs and arrays, []interface{} for
+// other vecs and arrays, nil for empty options and map[string]interface{} for structs.
+func (i *IDL) DecodeInstruction(data []byte, accounts []solana.PublicKey) (*DecodedInstruction, error) {
+	for _, instruction := range i.Instructions {
+		if len(instruction.Discriminator) == 0 || !bytes.HasPrefix(data, instruction.Discriminator) {
+			continue
+		}
+		d := &decoder{idl: i, data: data, offset: len(instruction.Discriminator)}
+		args, err := d.fields(instruction.Args)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %s: %w", instruction.Name, err)
+		}
+		decoded := &DecodedInstruction{Name: instruction.Name, Args: args, Extra: d.rest()}
+		if accounts != nil {
+			decoded.Accounts = make(map[string]solana.PublicKey)
+			for j, account := range instruction.Accounts {
+				if j < len(accounts) {
+					decoded.Accounts[account.Name] = accounts[j]
+				}
+			}
+		}
+		return decoded, nil
+	}
+	return nil, fmt.Errorf("no instruction of %s matches the discriminator", i.Name)
+}
+
+// DecodeEvent decodes an event from "Program data:" logs or from a self-CPI event instruction
+func (i *IDL) DecodeEvent(data []byte) (*DecodedEvent, error) {
+	data = bytes.TrimPrefix(data, eventIxTag)
+	for _, event := range i.Events {
+		if len(event.Discriminator) == 0 || !bytes.HasPrefix(data, event.Discriminator) {
+			continue
+		}
+		d := &decoder{idl: i, data: data, offset: len(event.Discriminator)}
+		fields, err := d.fields(event.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.Name, err)
+		}
+		return &DecodedEvent{Name: event.Name, Fields: fields, Extra: d.rest()}, nil
+	}
+	return nil, fmt.Errorf("no event of %s matches the discriminator", i.Name)
+}
+
+// DecodeAccount decodes the data of an account owned by the program
+func (i *IDL) DecodeAccount(data []byte) (*DecodedAccount, error) {
+	for _, account := range i.Accounts {
+		discriminator := i.accountDiscriminators[account.Name]
+		if len(discriminator) == 0 || !bytes.HasPrefix(data, discriminator) {
+			continue
+		}
+		d := &decoder{idl: i, data: data, offset: len(discriminator)}
+		fields, err := d.fields(account.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", account.Name, err)
+		}
+		return &DecodedAccount{Name: account.Name, Fields: fields, Extra: d.rest()}, nil
+	}
+	return nil, fmt.Errorf("no account of %s matches the discriminator", i.Name)
+}
+
+// InstructionName returns the name of the instruction matching the discriminator of data, empty when none does
+func (i *IDL) InstructionName(data []byte) string {
+	for _, instruction := range i.Instructions {
+		if len(instruction.Discriminator) > 0 && bytes.HasPrefix(data, instruction.Discriminator) {
+			return instruction.Name
+		}
+	}
+	return ""
+}
+
+// decoder reads borsh values
+type decoder struct {
+	idl    *IDL
+	data   []byte
+	offset int
+}
+
+func (d *decoder) rest() []byte {
+	if d.offset >= len(d.data) {
+		return nil
+	}
+	return d.data[d.offset:]
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 || d.offset+n > len(d.data) {
+		return nil, fmt.Errorf("unexpected end of data at offset %d", d.offset)
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *decoder) fields(fields []Field) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value, err := d.value(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		values[field.Name] = value
+	}
+	return values, nil
+}
+
+func (d *decoder) tuple(types []Type) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(types))
+	for _, t := range types {
+		value, err := d.value(t)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (d *decoder) value(t Type) (interface{}, error) {
+	switch {
+	case t.Vec != nil:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		n := int(binary.LittleEndian.Uint32(b))
+		if t.Vec.Primitive == "u8" {
+			return d.read(n)
+		}
+		values := make([]interface{}, 0)
+		for j := 0; j < n; j++ {
+			value, err := d.value(*t.Vec)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case t.Option != nil || t.COption != nil:
+		inner, tagLen := t.Option, 1
+		if t.COption != nil {
+			inner, tagLen = t.COption, 4
+		}
+		tag, err := d.read(tagLen)
+		if err != nil {
+			return nil, err
+		}
+		if tag[0] == 0 {
+			return nil, nil
+		}
+		return d.value(*inner)
+	case t.Array != nil:
+		if t.Array.Primitive == "u8" {
+			return d.read(t.ArrayLen)
+		}
+		values := make([]interface{}, 0, t.ArrayLen)
+		for j := 0; j < t.ArrayLen; j++ {
+			value, err := d.value(*t.Array)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case t.Defined != "":
+		return d.defined(t.Defined)
+	}
+	return d.primitive(t.Primitive)
+}
+
+func (d *decoder) defined(name string) (interface{}, error) {
+	typeDef := d.idl.types[name]
+	if typeDef == nil {
+		return nil, fmt.Errorf("undefined type %s", name)
+	}
+	if typeDef.Alias != nil {
+		return d.value(*typeDef.Alias)
+	}
+	if typeDef.Kind == "struct" {
+		if typeDef.Tuple != nil {
+			return d.tuple(typeDef.Tuple)
+		}
+		return d.fields(typeDef.Fields)
+	}
+
+	tag, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	if int(tag[0]) >= len(typeDef.Variants) {
+		return nil, fmt.Errorf("enum %s has no variant %d", name, tag[0])
+	}
+	// unit variants decode to their name, the others to a map from their name to their fields
+	variant := typeDef.Variants[tag[0]]
+	switch {
+	case variant.Fields != nil:
+		fields, err := d.fields(variant.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{variant.Name: fields}, nil
+	case variant.Tuple != nil:
+		values, err := d.tuple(variant.Tuple)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{variant.Name: values}, nil
+	}
+	return variant.Name, nil
+}
+
+func (d *decoder) primitive(name string) (interface{}, error) {
+	sizes := map[string]int{
+		"bool": 1, "u8": 1, "i8": 1, "u16": 2, "i16": 2, "u32": 4, "i32": 4, "f32": 4,
+		"u64": 8, "i64": 8, "f64": 8, "u128": 16, "i128": 16, "pubkey": 32,
+	}
+	if name == "string" || name == "bytes" {
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.read(int(binary.LittleEndian.Uint32(b)))
+		if err != nil {
+			return nil, err
+		}
+		if name == "string" {
+			return string(value), nil
+		}
+		return value, nil
+	}
+
+	size, ok := sizes[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %q", name)
+	}
+	b, err := d.read(size)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "bool":
+		return b[0] != 0, nil
+	case "u8":
+		return b[0], nil
+	case "i8":
+		return int8(b[0]), nil
+	case "u16":
+		return binary.LittleEndian.Uint16(b), nil
+	case "i16":
+		return int16(binary.LittleEndian.Uint16(b)), nil
+	case "u32":
+		return binary.LittleEndian.Uint32(b), nil
+	case "i32":
+		return int32(binary.LittleEndian.Uint32(b)), nil
+	case "f32":
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+	case "u64":
+		return binary.LittleEndian.Uint64(b), nil
+	case "i64":
+		return int64(binary.LittleEndian.Uint64(b)), nil
+	case "f64":
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "u128", "i128":
+		return decodeInt128(b, name == "i128"), nil
+	}
+	return solana.PublicKeyFromBytes(b), nil
+}
+
+// decodeInt128 reads a little endian 128 bit integer
+func decodeInt128(b []byte, signed bool) *big.Int {
+	be := make([]byte, len(b))
+	for j := range b {
+		be[len(b)-1-j] = b[j]
+	}
+	value := new(big.Int).SetBytes(be)
+	if signed && b[len(b)-1]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	return value
+}
+
+// EventName returns the name of the event data starts with, empty when the IDL has none
+func (i *IDL) EventName(data []byte) string {
+	data = bytes.TrimPrefix(data, eventIxTag)
+	for _, event := range i.Events {
+		if len(event.Discriminator) > 0 && bytes.HasPrefix(data, event.Discriminator) {
+			return event.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,402 @@
+// Package idl loads Anchor IDL files and decodes the instructions, events and accounts of a program
+// by discriminator, so that Anchor based programs can be supported without hand written decoders.
+package idl
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// IDL is an Anchor IDL, in the legacy format or the format of Anchor 0.30 and later
+type IDL struct {
+	Address      solana.PublicKey
+	Name         string
+	Version      string
+	Instructions []Instruction
+	Accounts     []TypeDef // account structs, see AccountDiscriminator
+	Events       []Event
+	Types        []TypeDef
+
+	accountDiscriminators map[string][]byte
+	types                 map[string]*TypeDef
+}
+
+type Instruction struct {
+	Name          string
+	Discriminator []byte
+	Accounts      []AccountItem
+	Args          []Field
+}
+
+// AccountItem is an account of an instruction, account groups are flattened
+type AccountItem struct {
+	Name     string
+	Writable bool
+	Signer   bool
+	Optional bool
+}
+
+type Event struct {
+	Name          string
+	Discriminator []byte
+	Fields        []Field
+}
+
+type Field struct {
+	Name string `json:"name"`
+	Type Type   `json:"type"`
+}
+
+// TypeDef is a struct, an enum or a type alias defined by the IDL
+type TypeDef struct {
+	Name     string
+	Kind     string // "struct", "enum" or "type"
+	Fields   []Field
+	Tuple    []Type // fields of tuple structs
+	Variants []Variant
+	Alias    *Type // aliased type of "type" definitions
+}
+
+// Variant is an enum variant, with named fields, tuple fields or none
+type Variant struct {
+	Name   string
+	Fields []Field
+	Tuple  []Type
+}
+
+// Type is an IDL type: a primitive, a vec, an option, a fixed array or a defined type
+type Type struct {
+	Primitive string
+	Vec       *Type
+	Option    *Type
+	COption   *Type
+	Array     *Type
+	ArrayLen  int
+	Defined   string
+}
+
+func (t *Type) UnmarshalJSON(data []byte) error {
+	var primitive string
+	if err := json.Unmarshal(data, &primitive); err == nil {
+		if primitive == "publicKey" {
+			primitive = "pubkey"
+		}
+		t.Primitive = primitive
+		return nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	switch {
+	case object["vec"] != nil:
+		t.Vec = &Type{}
+		return json.Unmarshal(object["vec"], t.Vec)
+	case object["option"] != nil:
+		t.Option = &Type{}
+		return json.Unmarshal(object["option"], t.Option)
+	case object["coption"] != nil:
+		t.COption = &Type{}
+		return json.Unmarshal(object["coption"], t.COption)
+	case object["array"] != nil:
+		var array []json.RawMessage
+		if err := json.Unmarshal(object["array"], &array); err != nil || len(array) != 2 {
+			return fmt.Errorf("invalid array type %s", object["array"])
+		}
+		t.Array = &Type{}
+		if err := json.Unmarshal(array[0], t.Array); err != nil {
+			return err
+		}
+		// the length is a number, or a generic or constant name that cannot be decoded
+		if err := json.Unmarshal(array[1], &t.ArrayLen); err != nil {
+			return fmt.Errorf("unsupported array length %s", array[1])
+		}
+		return nil
+	case object["defined"] != nil:
+		// legacy: "defined": "Name", 0.30: "defined": {"name": "Name"}
+		if err := json.Unmarshal(object["defined"], &t.Defined); err == nil {
+			return nil
+		}
+		var defined struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(object["defined"], &defined); err != nil {
+			return err
+		}
+		t.Defined = defined.Name
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", data)
+}
+
+type rawIDL struct {
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Metadata struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Address string `json:"address"`
+	} `json:"metadata"`
+	Instructions []struct {
+		Name          string            `json:"name"`
+		Discriminator []int             `json:"discriminator"`
+		Accounts      []json.RawMessage `json:"accounts"`
+		Args          []Field           `json:"args"`
+	} `json:"instructions"`
+	Accounts []struct {
+		Name          string      `json:"name"`
+		Discriminator []int       `json:"discriminator"`
+		Type          *rawTypeDef `json:"type"`
+	} `json:"accounts"`
+	Events []struct {
+		Name          string  `json:"name"`
+		Discriminator []int   `json:"discriminator"`
+		Fields        []Field `json:"fields"`
+	} `json:"events"`
+	Types []struct {
+		Name string     `json:"name"`
+		Type rawTypeDef `json:"type"`
+	} `json:"types"`
+}
+
+type rawTypeDef struct {
+	Kind     string            `json:"kind"`
+	Fields   []json.RawMessage `json:"fields"`
+	Variants []struct {
+		Name   string            `json:"name"`
+		Fields []json.RawMessage `json:"fields"`
+	} `json:"variants"`
+	Alias *Type `json:"alias"`
+}
+
+type rawAccountItem struct {
+	Name       string            `json:"name"`
+	IsMut      bool              `json:"isMut"`
+	IsSigner   bool              `json:"isSigner"`
+	IsOptional bool              `json:"isOptional"`
+	Writable   bool              `json:"writable"`
+	Signer     bool              `json:"signer"`
+	Optional   bool              `json:"optional"`
+	Accounts   []json.RawMessage `json:"accounts"`
+}
+
+// Parse decodes an Anchor IDL, legacy IDLs get the discriminators Anchor derives from the names
+func Parse(data []byte) (*IDL, error) {
+	var raw rawIDL
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	idl := &IDL{
+		Name:                  raw.Name,
+		Version:               raw.Version,
+		accountDiscriminators: make(map[string][]byte),
+		types:                 make(map[string]*TypeDef),
+	}
+	legacy := raw.Address == ""
+	address := raw.Address
+	if legacy {
+		address = raw.Metadata.Address
+	} else {
+		idl.Name, idl.Version = raw.Metadata.Name, raw.Metadata.Version
+	}
+	if address != "" {
+		programID, err := solana.PublicKeyFromBase58(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid program address %q: %w", address, err)
+		}
+		idl.Address = programID
+	}
+
+	for _, rawType := range raw.Types {
+		typeDef, err := rawType.Type.typeDef(rawType.Name)
+		if err != nil {
+			return nil, err
+		}
+		idl.Types = append(idl.Types, *typeDef)
+	}
+	for i := range idl.Types {
+		idl.types[idl.Types[i].Name] = &idl.Types[i]
+	}
+
+	for _, rawInstruction := range raw.Instructions {
+		instruction := Instruction{Name: rawInstruction.Name, Args: rawInstruction.Args}
+		if legacy {
+			instruction.Discriminator = sighash("global", toSnakeCase(rawInstruction.Name))
+		} else {
+			instruction.Discriminator = toBytes(rawInstruction.Discriminator)
+		}
+		accounts, err := flattenAccounts(rawInstruction.Accounts)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %s: %w", rawInstruction.Name, err)
+		}
+		instruction.Accounts = accounts
+		idl.Instructions = append(idl.Instructions, instruction)
+	}
+
+	for _, rawAccount := range raw.Accounts {
+		var typeDef *TypeDef
+		switch {
+		case rawAccount.Type != nil:
+			var err error
+			if typeDef, err = rawAccount.Type.typeDef(rawAccount.Name); err != nil {
+				return nil, err
+			}
+		case idl.types[rawAccount.Name] != nil:
+			typeDef = idl.types[rawAccount.Name]
+		default:
+			return nil, fmt.Errorf("account %s has no type", rawAccount.Name)
+		}
+		idl.Accounts = append(idl.Accounts, *typeDef)
+		if legacy || len(rawAccount.Discriminator) == 0 {
+			idl.accountDiscriminators[rawAccount.Name] = sighash("account", rawAccount.Name)
+		} else {
+			idl.accountDiscriminators[rawAccount.Name] = toBytes(rawAccount.Discriminator)
+		}
+	}
+
+	for _, rawEvent := range raw.Events {
+		event := Event{Name: rawEvent.Name, Fields: rawEvent.Fields}
+		if legacy || len(rawEvent.Discriminator) == 0 {
+			event.Discriminator = sighash("event", rawEvent.Name)
+		} else {
+			event.Discriminator = toBytes(rawEvent.Discriminator)
+		}
+		// since 0.30 the fields of an event are the type of the same name
+		if event.Fields == nil && idl.types[rawEvent.Name] != nil {
+			event.Fields = idl.types[rawEvent.Name].Fields
+		}
+		idl.Events = append(idl.Events, event)
+	}
+
+	return idl, nil
+}
+
+// AccountDiscriminator returns the discriminator of an account struct
+func (i *IDL) AccountDiscriminator(name string) []byte {
+	return i.accountDiscriminators[name]
+}
+
+// Instruction returns the instruction named name, nil when the IDL has none
+func (i *IDL) Instruction(name string) *Instruction {
+	for j := range i.Instructions {
+		if i.Instructions[j].Name == name {
+			return &i.Instructions[j]
+		}
+	}
+	return nil
+}
+
+func (r rawTypeDef) typeDef(name string) (*TypeDef, error) {
+	typeDef := &TypeDef{Name: name, Kind: r.Kind}
+	switch r.Kind {
+	case "struct":
+		fields, tuple, err := parseFields(r.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", name, err)
+		}
+		typeDef.Fields, typeDef.Tuple = fields, tuple
+	case "enum":
+		for _, rawVariant := range r.Variants {
+			fields, tuple, err := parseFields(rawVariant.Fields)
+			if err != nil {
+				return nil, fmt.Errorf("type %s variant %s: %w", name, rawVariant.Name, err)
+			}
+			typeDef.Variants = append(typeDef.Variants, Variant{Name: rawVariant.Name, Fields: fields, Tuple: tuple})
+		}
+	case "type":
+		if r.Alias == nil {
+			return nil, fmt.Errorf("type %s has no alias", name)
+		}
+		typeDef.Alias = r.Alias
+	default:
+		return nil, fmt.Errorf("type %s has unsupported kind %q", name, r.Kind)
+	}
+	return typeDef, nil
+}
+
+// parseFields reads named fields ({"name", "type"}) or tuple fields (bare types)
+func parseFields(raw []json.RawMessage) ([]Field, []Type, error) {
+	var fields []Field
+	var tuple []Type
+	for _, item := range raw {
+		var probe map[string]json.RawMessage
+		if json.Unmarshal(item, &probe) == nil && probe["type"] != nil && probe["name"] != nil {
+			var field Field
+			if err := json.Unmarshal(item, &field); err != nil {
+				return nil, nil, err
+			}
+			fields = append(fields, field)
+			continue
+		}
+		var t Type
+		if err := json.Unmarshal(item, &t); err != nil {
+			return nil, nil, err
+		}
+		tuple = append(tuple, t)
+	}
+	return fields, tuple, nil
+}
+
+func flattenAccounts(raw []json.RawMessage) ([]AccountItem, error) {
+	var accounts []AccountItem
+	for _, item := range raw {
+		var account rawAccountItem
+		if err := json.Unmarshal(item, &account); err != nil {
+			return nil, err
+		}
+		if account.Accounts != nil {
+			nested, err := flattenAccounts(account.Accounts)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, nested...)
+			continue
+		}
+		accounts = append(accounts, AccountItem{
+			Name:     account.Name,
+			Writable: account.IsMut || account.Writable,
+			Signer:   account.IsSigner || account.Signer,
+			Optional: account.IsOptional || account.Optional,
+		})
+	}
+	return accounts, nil
+}
+
+func sighash(namespace string, name string) []byte {
+	hash := sha256.Sum256([]byte(namespace + ":" + name))
+	return hash[:8]
+}
+
+func toBytes(values []int) []byte {
+	result := make([]byte, len(values))
+	for i, v := range values {
+		result[i] = byte(v)
+	}
+	return result
+}
+
+// toSnakeCase converts the camelCase instruction names of legacy IDLs to the snake_case names Anchor hashes
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
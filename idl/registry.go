@@ -0,0 +1,63 @@
+package idl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[solana.PublicKey]*IDL)
+)
+
+// Register makes the IDL available to Lookup under its program address
+func Register(idl *IDL) error {
+	if idl.Address.IsZero() {
+		return fmt.Errorf("IDL %s has no program address", idl.Name)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[idl.Address] = idl
+	return nil
+}
+
+// Lookup returns the registered IDL of a program, nil when there is none
+func Lookup(programID solana.PublicKey) *IDL {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[programID]
+}
+
+// LoadFile parses the IDL JSON file at path and registers it
+func LoadFile(path string) (*IDL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read IDL: %w", err)
+	}
+	idl, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IDL %s: %w", path, err)
+	}
+	if err := Register(idl); err != nil {
+		return nil, fmt.Errorf("failed to register IDL %s: %w", path, err)
+	}
+	return idl, nil
+}
+
+// LoadDir loads and registers every .json IDL file of a directory
+func LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if _, err := LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
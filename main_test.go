@@ -1,14 +1,22 @@
 package solanaswapgo
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lonelybeanz/solanaswap-go/idl"
+	"github.com/lonelybeanz/solanaswap-go/quote"
 	solanaswapgo "github.com/lonelybeanz/solanaswap-go/solanaswap-go"
 )
 
@@ -100,3 +108,1405 @@ func TestCreate(t *testing.T) {
 	fmt.Println(string(marshalledSwapData))
 
 }
+
+func TestParseProgramLogs(t *testing.T) {
+	logs := []string{
+		"Program ComputeBudget111111111111111111111111111111 invoke [1]",
+		"Program ComputeBudget111111111111111111111111111111 success",
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 invoke [1]",
+		"Program log: Instruction: Route",
+		"Program 6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P invoke [2]",
+		"Program log: Instruction: Buy",
+		"Program data: AQID",
+		"Program 6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P consumed 30000 of 180000 compute units",
+		"Program 6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P failed: custom program error: 0x1772",
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 consumed 50000 of 200000 compute units",
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 failed: custom program error: 0x1772",
+	}
+
+	roots := solanaswapgo.ParseProgramLogs(logs)
+	if len(roots) != 2 {
+		t.Fatalf("expected 2 top level invocations, got %d", len(roots))
+	}
+	jupiter := roots[1]
+	if jupiter.ComputeUnits != 50000 || jupiter.ComputeUnitsLimit != 200000 || !jupiter.Failed {
+		t.Fatalf("unexpected Jupiter invocation: %+v", jupiter)
+	}
+	if len(jupiter.Children) != 1 {
+		t.Fatalf("expected 1 CPI, got %d", len(jupiter.Children))
+	}
+	pump := jupiter.Children[0]
+	if pump.Depth != 2 || pump.ComputeUnits != 30000 || pump.Error != "custom program error: 0x1772" {
+		t.Fatalf("unexpected pump.fun invocation: %+v", pump)
+	}
+	if len(pump.Messages) != 1 || pump.Messages[0] != "Instruction: Buy" {
+		t.Fatalf("unexpected messages: %v", pump.Messages)
+	}
+	if len(pump.Data) != 1 || len(pump.Data[0]) != 3 {
+		t.Fatalf("unexpected program data: %v", pump.Data)
+	}
+}
+
+func TestIDLDecode(t *testing.T) {
+	legacy := []byte(`{
+		"version": "0.1.0",
+		"name": "amm",
+		"instructions": [{
+			"name": "swapBaseInput",
+			"accounts": [{"name": "payer", "isMut": false, "isSigner": true}, {"name": "pool", "isMut": true, "isSigner": false}],
+			"args": [{"name": "amountIn", "type": "u64"}, {"name": "minimumAmountOut", "type": "u64"}]
+		}],
+		"events": [{
+			"name": "SwapEvent",
+			"fields": [
+				{"name": "pool", "type": "publicKey", "index": false},
+				{"name": "side", "type": {"defined": "Side"}, "index": false},
+				{"name": "memo", "type": {"option": "string"}, "index": false}
+			]
+		}],
+		"types": [{"name": "Side", "type": {"kind": "enum", "variants": [{"name": "Buy"}, {"name": "Sell"}]}}],
+		"metadata": {"address": "CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C"}
+	}`)
+	parsed, err := idl.Parse(legacy)
+	if err != nil {
+		t.Fatalf("failed to parse legacy IDL: %s", err)
+	}
+
+	data := append([]byte{143, 190, 90, 218, 196, 30, 51, 222}, 0x10, 0, 0, 0, 0, 0, 0, 0, 0x20, 0, 0, 0, 0, 0, 0, 0, 0xff)
+	payer, pool := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	instruction, err := parsed.DecodeInstruction(data, []solana.PublicKey{payer, pool})
+	if err != nil {
+		t.Fatalf("failed to decode instruction: %s", err)
+	}
+	if instruction.Name != "swapBaseInput" || instruction.Args["amountIn"] != uint64(16) || instruction.Args["minimumAmountOut"] != uint64(32) {
+		t.Fatalf("unexpected instruction: %+v", instruction)
+	}
+	if !instruction.Accounts["pool"].Equals(pool) || len(instruction.Extra) != 1 {
+		t.Fatalf("unexpected accounts or extra bytes: %+v", instruction)
+	}
+
+	event := append(append([]byte{}, parsed.Events[0].Discriminator...), pool.Bytes()...)
+	event = append(event, 1, 1, 2, 0, 0, 0, 'h', 'i')
+	decoded, err := parsed.DecodeEvent(event)
+	if err != nil {
+		t.Fatalf("failed to decode event: %s", err)
+	}
+	if decoded.Fields["side"] != "Sell" || decoded.Fields["memo"] != "hi" || !decoded.Fields["pool"].(solana.PublicKey).Equals(pool) {
+		t.Fatalf("unexpected event: %+v", decoded)
+	}
+
+	current := []byte(`{
+		"address": "CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C",
+		"metadata": {"name": "amm", "version": "0.2.0", "spec": "0.1.0"},
+		"instructions": [{
+			"name": "swap_base_input",
+			"discriminator": [143, 190, 90, 218, 196, 30, 51, 222],
+			"accounts": [{"name": "payer", "signer": true}, {"name": "pool", "writable": true}],
+			"args": [{"name": "amount_in", "type": "u64"}, {"name": "minimum_amount_out", "type": "u64"}]
+		}],
+		"events": [{"name": "SwapEvent", "discriminator": [1, 2, 3, 4, 5, 6, 7, 8]}],
+		"types": [
+			{"name": "SwapEvent", "type": {"kind": "struct", "fields": [{"name": "amount", "type": "u128"}, {"name": "fees", "type": {"defined": {"name": "Fees"}}}]}},
+			{"name": "Fees", "type": {"kind": "type", "alias": {"array": ["u64", 2]}}}
+		]
+	}`)
+	parsed, err = idl.Parse(current)
+	if err != nil {
+		t.Fatalf("failed to parse IDL: %s", err)
+	}
+	if name := parsed.InstructionName(data); name != "swap_base_input" {
+		t.Fatalf("unexpected instruction name %q", name)
+	}
+	event = append([]byte{1, 2, 3, 4, 5, 6, 7, 8, 42}, make([]byte, 15)...)
+	event = binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(event, 3), 5)
+	decoded, err = parsed.DecodeEvent(event)
+	if err != nil {
+		t.Fatalf("failed to decode event: %s", err)
+	}
+	if amount := decoded.Fields["amount"].(*big.Int); amount.Int64() != 42 {
+		t.Fatalf("unexpected amount %s", amount)
+	}
+	// the alias decodes as the type it names
+	if fees, ok := decoded.Fields["fees"].([]interface{}); !ok || len(fees) != 2 || fees[0] != uint64(3) || fees[1] != uint64(5) {
+		t.Fatalf("unexpected fees %v", decoded.Fields["fees"])
+	}
+	if fees := parsed.Types[1]; fees.Kind != "type" || fees.Alias == nil || fees.Alias.Array == nil || fees.Alias.ArrayLen != 2 {
+		t.Fatalf("unexpected alias %+v", fees)
+	}
+}
+
+func TestDecodeEventVersions(t *testing.T) {
+	key := func() []byte { return solana.NewWallet().PublicKey().Bytes() }
+	u64 := func(v uint64) []byte { return binary.LittleEndian.AppendUint64(nil, v) }
+	join := func(parts ...[]byte) []byte {
+		var data []byte
+		for _, part := range parts {
+			data = append(data, part...)
+		}
+		return data
+	}
+
+	// TradeEvent: v1 mint, sol, token, is_buy, user, timestamp, virtual reserves / v2 real reserves /
+	// v3 fee recipient, fees and creator / v4 volume tracking
+	tradeV1 := join([]byte{189, 219, 127, 211, 78, 230, 97, 238}, key(), u64(1_000_000), u64(35_000_000_000), []byte{1}, key(), u64(1_700_000_000),
+		u64(31_000_000_000), u64(1_038_000_000_000_000))
+	tradeV2 := join(tradeV1, u64(1_000_000), u64(758_000_000_000_000))
+	tradeV3 := join(tradeV2, key(), u64(95), u64(9_500), key(), u64(5), u64(500))
+	tradeV4 := join(tradeV3, []byte{1}, u64(7), u64(8), u64(9), u64(1_700_000_001))
+
+	trades := []struct {
+		name    string
+		data    []byte
+		version int
+		extra   []byte
+	}{
+		{"v1", tradeV1, 1, nil},
+		{"v2", tradeV2, 2, nil},
+		{"v3", tradeV3, 3, nil},
+		{"v4", tradeV4, 4, nil},
+		{"v3 with part of v4", join(tradeV3, []byte{1}, u64(7)), 3, join([]byte{1}, u64(7))},
+		{"unknown trailing bytes", join(tradeV4, []byte{0xde, 0xad}), 4, []byte{0xde, 0xad}},
+	}
+	for _, test := range trades {
+		trade, err := solanaswapgo.DecodePumpfunTradeEvent(test.data)
+		if err != nil {
+			t.Fatalf("TradeEvent %s: %s", test.name, err)
+		}
+		if trade.Version != test.version || !bytes.Equal(trade.Extra, test.extra) || trade.SolAmount != 1_000_000 || !trade.IsBuy {
+			t.Fatalf("TradeEvent %s: %+v", test.name, trade)
+		}
+		if (test.version >= 2) != (trade.RealTokenReserves == 758_000_000_000_000) ||
+			(test.version >= 3) != (trade.CreatorFee == 500) ||
+			(test.version >= 4) != (trade.LastUpdateTimestamp == 1_700_000_001) {
+			t.Fatalf("TradeEvent %s: fields do not match version %d: %+v", test.name, test.version, trade)
+		}
+		// fields read past the last complete version are zeroed
+		if test.version < 4 && (trade.TrackVolume || trade.TotalUnclaimedTokens != 0) {
+			t.Fatalf("TradeEvent %s: partial v4 fields kept: %+v", test.name, trade)
+		}
+	}
+	if _, err := solanaswapgo.DecodePumpfunTradeEvent(tradeV1[:40]); err == nil {
+		t.Fatal("TradeEvent shorter than v1 decoded")
+	}
+
+	// BuyEvent: v1 amounts, reserves and fees, pool and user accounts / v2 coin creator fee / v3 volume tracking
+	buyV1 := join([]byte{103, 244, 82, 31, 44, 245, 119, 119}, u64(1_700_000_000), u64(2_000_000), u64(1_100_000), u64(0), u64(5_000_000_000),
+		u64(206_900_000_000_000), u64(84_990_359_057), u64(1_000_000), u64(20), u64(2_000), u64(5), u64(500), u64(1_002_000), u64(1_002_500),
+		key(), key(), key(), key(), key(), key())
+	buyV2 := join(buyV1, key(), u64(5), u64(500))
+	buyV3 := join(buyV2, []byte{0}, u64(1), u64(2), u64(3), u64(1_700_000_001))
+
+	buys := []struct {
+		name    string
+		data    []byte
+		version int
+		extra   []byte
+	}{
+		{"v1", buyV1, 1, nil},
+		{"v2", buyV2, 2, nil},
+		{"v3", buyV3, 3, nil},
+		{"unknown trailing bytes", join(buyV3, []byte{0xbe, 0xef}), 3, []byte{0xbe, 0xef}},
+	}
+	for _, test := range buys {
+		event, err := solanaswapgo.DecodePumpAmmEvent(test.data)
+		if err != nil || event.Buy == nil {
+			t.Fatalf("BuyEvent %s: %+v, %v", test.name, event, err)
+		}
+		buy := event.Buy
+		if buy.Version != test.version || !bytes.Equal(buy.Extra, test.extra) || buy.BaseAmountOut != 2_000_000 ||
+			buy.PoolQuoteTokenReserves != 84_990_359_057 || buy.UserQuoteAmountIn != 1_002_500 {
+			t.Fatalf("BuyEvent %s: %+v", test.name, buy)
+		}
+		if (test.version >= 2) != (buy.CoinCreatorFee == 500) || (test.version >= 3) != (buy.LastUpdateTimestamp == 1_700_000_001) {
+			t.Fatalf("BuyEvent %s: fields do not match version %d: %+v", test.name, test.version, buy)
+		}
+	}
+}
+
+func TestParseIDLEvents(t *testing.T) {
+	program := solana.NewWallet().PublicKey()
+	parsed, err := idl.Parse([]byte(fmt.Sprintf(`{
+		"address": "%s",
+		"metadata": {"name": "amm", "version": "0.1.0", "spec": "0.1.0"},
+		"instructions": [],
+		"events": [{"name": "SwapEvent", "discriminator": [1, 2, 3, 4, 5, 6, 7, 8]}],
+		"types": [{"name": "SwapEvent", "type": {"kind": "struct", "fields": [{"name": "amount", "type": "u64"}]}}]
+	}`, program)))
+	if err != nil {
+		t.Fatalf("failed to parse IDL: %s", err)
+	}
+	if err := idl.Register(parsed); err != nil {
+		t.Fatalf("failed to register IDL: %s", err)
+	}
+
+	// the program emits its event through a self-CPI
+	event := append([]byte{228, 69, 165, 46, 81, 203, 154, 29, 1, 2, 3, 4, 5, 6, 7, 8}, binary.LittleEndian.AppendUint64(nil, 42)...)
+	tx := &solana.Transaction{Message: solana.Message{
+		Header:       solana.MessageHeader{NumRequiredSignatures: 1},
+		AccountKeys:  solana.PublicKeySlice{solana.NewWallet().PublicKey(), program},
+		Instructions: []solana.CompiledInstruction{{ProgramIDIndex: 1, Accounts: []uint16{0}}},
+	}}
+	meta := &rpc.TransactionMeta{InnerInstructions: []rpc.InnerInstruction{{Index: 0, Instructions: []rpc.CompiledInstruction{
+		{ProgramIDIndex: 1, Data: event, StackHeight: 2},
+	}}}}
+	parser, err := solanaswapgo.NewTransactionParserFromTransaction(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to create parser: %s", err)
+	}
+	events := parser.ParseIDLEvents()
+	if len(events) != 1 || !events[0].ProgramID.Equals(program) || events[0].Event.Name != "SwapEvent" ||
+		events[0].Event.Fields["amount"] != uint64(42) {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestDecodeDCAFilledEvent(t *testing.T) {
+	usdc := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	user := solana.MustPublicKeyFromBase58("5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1")
+	dca := solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	amount := func(v uint64) []byte { return binary.LittleEndian.AppendUint64(nil, v) }
+
+	// Filled { user_key, dca_key, input_mint, output_mint, in_amount, out_amount, fee_mint, fee }
+	data := append([]byte{134, 4, 17, 63, 221, 45, 177, 173}, user.Bytes()...)
+	data = append(data, dca.Bytes()...)
+	data = append(data, solana.SolMint.Bytes()...)
+	data = append(data, usdc.Bytes()...)
+	data = append(data, amount(100_000_000)...)
+	data = append(data, amount(16_512_345)...)
+	data = append(data, usdc.Bytes()...)
+	data = append(data, amount(16_512)...)
+
+	event, err := solanaswapgo.DecodeDCAEvent(data)
+	if err != nil || event == nil || event.Name != "Filled" {
+		t.Fatalf("failed to decode Filled event: %+v, %v", event, err)
+	}
+	fill := event.Data.(*solanaswapgo.DCAFilledEvent)
+	if !fill.UserKey.Equals(user) || !fill.DcaKey.Equals(dca) || !fill.InputMint.Equals(solana.SolMint) ||
+		!fill.OutputMint.Equals(usdc) || fill.InAmount != 100_000_000 || fill.OutAmount != 16_512_345 || fill.Fee != 16_512 {
+		t.Fatalf("unexpected fill: %+v", fill)
+	}
+}
+
+func TestProgramCatalogOverride(t *testing.T) {
+	bot, feeWallet := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	path := filepath.Join(t.TempDir(), "catalog.yaml")
+	catalog := fmt.Sprintf("programs:\n  - name: TestBot\n    category: bot\n    strategy: transfer-based\n    swap_type: TestAMM\n    program_ids: [%s]\n    fee_wallets: [%s]\n",
+		bot, feeWallet)
+	if err := os.WriteFile(path, []byte(catalog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := solanaswapgo.LoadProgramCatalog(path); err != nil {
+		t.Fatalf("failed to load catalog: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := solanaswapgo.LoadProgramCatalog("solanaswap-go/catalog.json"); err != nil {
+			t.Fatalf("failed to restore catalog: %s", err)
+		}
+	})
+
+	// the user pays 1 USDC to the pool of the bot and receives 2 TOKEN back, then 0.005 SOL to the bot fee wallet
+	signer, poolAuthority := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	usdc, token := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"), solana.NewWallet().PublicKey()
+	keys := solana.PublicKeySlice{
+		signer,
+		solana.NewWallet().PublicKey(), // user USDC
+		solana.NewWallet().PublicKey(), // pool USDC
+		solana.NewWallet().PublicKey(), // pool TOKEN
+		solana.NewWallet().PublicKey(), // user TOKEN
+		usdc,
+		token,
+		poolAuthority,
+		bot,
+		solana.TokenProgramID,
+		feeWallet,
+		solana.SystemProgramID,
+	}
+	transferChecked := func(source, mint, destination, authority uint16, amount uint64, decimals uint8) rpc.CompiledInstruction {
+		data := append(binary.LittleEndian.AppendUint64([]byte{12}, amount), decimals)
+		return rpc.CompiledInstruction{ProgramIDIndex: 9, Accounts: []uint16{source, mint, destination, authority}, Data: data, StackHeight: 2}
+	}
+	balance := func(account uint16, mint solana.PublicKey, owner solana.PublicKey, decimals uint8) rpc.TokenBalance {
+		return rpc.TokenBalance{AccountIndex: account, Mint: mint, Owner: &owner, UiTokenAmount: &rpc.UiTokenAmount{Amount: "0", Decimals: decimals}}
+	}
+	tx := &solana.Transaction{Message: solana.Message{
+		Header:      solana.MessageHeader{NumRequiredSignatures: 1},
+		AccountKeys: keys,
+		Instructions: []solana.CompiledInstruction{
+			{ProgramIDIndex: 8, Accounts: []uint16{0, 1, 2, 3, 4, 5, 6, 7, 9}},
+			{ProgramIDIndex: 11, Accounts: []uint16{0, 10}, Data: binary.LittleEndian.AppendUint64([]byte{2, 0, 0, 0}, 5_000_000)},
+		},
+	}}
+	meta := &rpc.TransactionMeta{
+		InnerInstructions: []rpc.InnerInstruction{{Index: 0, Instructions: []rpc.CompiledInstruction{
+			transferChecked(1, 5, 2, 0, 1_000_000, 6),
+			transferChecked(3, 6, 4, 7, 2_000_000_000, 9),
+		}}},
+		PostTokenBalances: []rpc.TokenBalance{
+			balance(1, usdc, signer, 6), balance(2, usdc, poolAuthority, 6),
+			balance(3, token, poolAuthority, 9), balance(4, token, signer, 9),
+		},
+	}
+
+	parser, err := solanaswapgo.NewTransactionParserFromTransaction(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to create parser: %s", err)
+	}
+	swapData, err := parser.ParseTransactionForSwap()
+	if err != nil {
+		t.Fatalf("failed to parse swap: %s", err)
+	}
+	swapInfo, err := parser.ProcessSwapData(swapData)
+	if err != nil {
+		t.Fatalf("failed to process swap data: %s", err)
+	}
+	if swapInfo.Frontend != "TestBot" || !swapInfo.TokenInMint.Equals(usdc) || swapInfo.TokenInAmount != 1_000_000 ||
+		!swapInfo.TokenOutMint.Equals(token) || swapInfo.TokenOutAmount != 2_000_000_000 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	if len(swapInfo.AMMs) == 0 || swapInfo.AMMs[0] != "TestAMM" {
+		t.Fatalf("unexpected AMMs: %v", swapInfo.AMMs)
+	}
+	// the swap input paid to the pool is not a fee
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "bot" || !swapInfo.Fees[0].Recipient.Equals(feeWallet) ||
+		swapInfo.Fees[0].Amount != 5_000_000 {
+		t.Fatalf("unexpected fees: %+v", swapInfo.Fees)
+	}
+}
+
+func TestPumpfunDevBuy(t *testing.T) {
+	dev, sniper, mint, curve := newKey(), newKey(), newKey(), newKey()
+	trade := func(user solana.PublicKey, sol uint64, tokens uint64) []byte {
+		return borsh(solanaswapgo.PumpfunTradeEventDiscriminator[:], mint, sol, tokens, true, user, int64(1_700_000_000),
+			uint64(31_000_000_000), uint64(1_038_000_000_000_000))
+	}
+
+	buyData := []byte{102, 6, 61, 18, 1, 218, 235, 234}
+
+	// a sniper buy lands in the create transaction before the dev buy
+	f := newTxFixture(dev)
+	create := f.instruction(solanaswapgo.PUMP_FUN_PROGRAM_ID, []solana.PublicKey{mint, curve, dev}, solanaswapgo.PumpfunCreateDiscriminator[:])
+	f.cpi(create, 2, solanaswapgo.PUMP_FUN_PROGRAM_ID, nil,
+		borsh(solanaswapgo.PumpfunCreateEventDiscriminator[:], "Token", "TKN", "https://example.com", mint, curve, dev))
+	snipe := f.instruction(solanaswapgo.PUMP_FUN_PROGRAM_ID, []solana.PublicKey{mint, curve, sniper}, buyData)
+	f.cpi(snipe, 2, solanaswapgo.PUMP_FUN_PROGRAM_ID, nil, trade(sniper, 500_000_000, 17_000_000_000_000))
+	buy := f.instruction(solanaswapgo.PUMP_FUN_PROGRAM_ID, []solana.PublicKey{mint, curve, dev}, buyData)
+	f.cpi(buy, 2, solanaswapgo.PUMP_FUN_PROGRAM_ID, nil, trade(dev, 1_000_000_000, 34_000_000_000_000))
+
+	launch, err := f.parser(t).ParseTransactionForMint()
+	if err != nil {
+		t.Fatalf("failed to parse mint: %s", err)
+	}
+	if !launch.Mint.Equals(mint) || launch.Name != "Token" || launch.Instruction != "create" {
+		t.Fatalf("unexpected launch: %+v", launch)
+	}
+	if launch.DevBuy == nil || !launch.DevBuy.User.Equals(dev) || launch.DevBuy.SolAmount != 1_000_000_000 {
+		t.Fatalf("unexpected dev buy: %+v", launch.DevBuy)
+	}
+}
+
+func TestPumpFunPoolProgress(t *testing.T) {
+	pool := &solanaswapgo.PumpFunPool{
+		VirtualSolReserves:   solanaswapgo.PumpfunInitialVirtualSolReserves,
+		VirtualTokenReserves: solanaswapgo.PumpfunInitialVirtualTokenReserves,
+		RealTokenReserves:    solanaswapgo.PumpfunInitialRealTokenReserves,
+	}
+	if pool.Progress() != 0 {
+		t.Fatalf("new curve progress: %f", pool.Progress())
+	}
+	if marketCap := pool.MarketCapSol(); marketCap != 27_958_993_476 {
+		t.Fatalf("new curve market cap: %d", marketCap)
+	}
+	// about 85 SOL graduate a new curve
+	if sol := pool.SolToComplete(); sol != 85_005_359_057 {
+		t.Fatalf("new curve SOL to complete: %d", sol)
+	}
+
+	pool.RealTokenReserves = 0
+	if pool.Progress() != 1 || pool.SolToComplete() != 0 {
+		t.Fatalf("completed curve: progress %f, SOL to complete %d", pool.Progress(), pool.SolToComplete())
+	}
+}
+
+func TestQuote(t *testing.T) {
+	pumpfun := quote.NewPumpfunCurve(&solanaswapgo.PumpFunPool{
+		VirtualSolReserves:   solanaswapgo.PumpfunInitialVirtualSolReserves,
+		VirtualTokenReserves: solanaswapgo.PumpfunInitialVirtualTokenReserves,
+		RealTokenReserves:    solanaswapgo.PumpfunInitialRealTokenReserves,
+	}, 95, 5)
+	buy, err := pumpfun.BuyExactOut(1_000_000_000_000)
+	if err != nil || buy.AmountIn != 28_264_927 || buy.Fee != 279_852 {
+		t.Fatalf("pump.fun buy: %+v, %v", buy, err)
+	}
+	sell, err := pumpfun.SellExactIn(1_000_000_000_000)
+	if err != nil || sell.AmountOut != 27_653_629 || sell.Fee != 279_331 {
+		t.Fatalf("pump.fun sell: %+v, %v", sell, err)
+	}
+
+	curves := map[string]quote.Curve{
+		"pump.fun": pumpfun,
+		"PumpAmm": quote.NewPumpAmmPool(&solanaswapgo.PumpAmmPool{
+			PoolBaseTokenReserves:  206_900_000_000_000,
+			PoolQuoteTokenReserves: 84_990_359_057,
+		}, 20, 5, 5),
+		"Raydium Launchpad": quote.NewRaydiumLaunchpadCurve(&solanaswapgo.RaydiumLaunchpadPool{
+			VirtualBase:     1_073_025_605_596_382,
+			VirtualQuote:    30_000_852_951,
+			RealBaseBefore:  100_000_000_000_000,
+			RealQuoteBefore: 3_000_000_000,
+		}, 2_500),
+	}
+	// small budgets on a pump.fun curve, where a lamport buys tens of thousands of token units
+	for _, quoteIn := range []uint64{77, 123_456_789} {
+		buy, err := pumpfun.BuyExactIn(quoteIn)
+		if err != nil || buy.AmountIn > quoteIn {
+			t.Fatalf("pump.fun buy of %d: %+v, %v", quoteIn, buy, err)
+		}
+		if next, err := pumpfun.BuyExactOut(buy.AmountOut + 1); err != nil || next.AmountIn <= quoteIn {
+			t.Fatalf("pump.fun buy of %d is not the largest: %+v, %v", quoteIn, next, err)
+		}
+	}
+
+	for name, curve := range curves {
+		buy, err := curve.BuyExactIn(1_000_000_000)
+		if err != nil || buy.AmountIn > 1_000_000_000 || buy.AmountOut == 0 {
+			t.Fatalf("%s buy exact in: %+v, %v", name, buy, err)
+		}
+		sell, err := curve.SellExactOut(1_000_000_000)
+		if err != nil || sell.AmountOut < 1_000_000_000 {
+			t.Fatalf("%s sell exact out: %+v, %v", name, sell, err)
+		}
+		if _, err := curve.SellExactIn(0); err != quote.ErrZeroAmount {
+			t.Fatalf("%s sell of zero: %v", name, err)
+		}
+	}
+}
+
+func TestPumpAmmPool(t *testing.T) {
+	user, pool, mint := newKey(), newKey(), newKey()
+	userBase, userQuote, poolBase, poolQuote, creatorVaultAta, creatorVault := newKey(), newKey(), newKey(), newKey(), newKey(), newKey()
+
+	// a buy with the 19 accounts of the IDL, ending with the coin creator vault
+	f := newTxFixture(user)
+	buy := f.instruction(solanaswapgo.PUMP_AMM_PROGRAM_ID, []solana.PublicKey{pool, user, newKey(), mint, solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID,
+		userBase, userQuote, poolBase, poolQuote, newKey(), newKey(), solana.TokenProgramID, solana.TokenProgramID, solana.SystemProgramID,
+		solana.SPLAssociatedTokenAccountProgramID, newKey(), solanaswapgo.PUMP_AMM_PROGRAM_ID, creatorVaultAta, creatorVault},
+		borsh(solanaswapgo.PumpAmmBuyDiscriminator[:], uint64(3_000_000_000), uint64(110_000_000)))
+	f.transfer(buy, 2, userQuote, solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID, poolQuote, user, 100_000_000, 9)
+	f.transfer(buy, 2, poolBase, mint, userBase, pool, 3_000_000_000, 6)
+	f.cpi(buy, 2, solanaswapgo.PUMP_AMM_PROGRAM_ID, []solana.PublicKey{newKey()},
+		borsh([]byte{228, 69, 165, 46, 81, 203, 154, 29}, solanaswapgo.PumpAmmBuyEventDiscriminator[:], int64(1_700_000_000),
+			uint64(3_000_000_000), uint64(110_000_000), uint64(0), uint64(100_000_000), uint64(197_000_000_000_000), uint64(85_100_000_000),
+			uint64(99_700_000), uint64(20), uint64(199_400), uint64(5), uint64(49_850), uint64(99_899_400), uint64(100_000_000),
+			pool, user, userBase, userQuote, newKey(), newKey()))
+	f.tokenAccount(userBase, mint, user, 6, 0, 3_000_000_000)
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID) || swapInfo.TokenInAmount != 100_000_000 ||
+		!swapInfo.TokenOutMint.Equals(mint) || swapInfo.TokenOutAmount != 3_000_000_000 || swapInfo.TokenOutDecimals != 6 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	if swapInfo.PoolData == nil {
+		t.Fatalf("missing pool data")
+	}
+	pumpAmmPool, ok := swapInfo.PoolData.Data.(*solanaswapgo.PumpAmmPool)
+	if !ok || !pumpAmmPool.Pool.Equals(pool) || !pumpAmmPool.BaseMint.Equals(mint) || !pumpAmmPool.PoolBaseTokenAccount.Equals(poolBase) ||
+		!pumpAmmPool.CoinCreatorVaultAta.Equals(creatorVaultAta) || !pumpAmmPool.CoinCreatorVaultAuthority.Equals(creatorVault) ||
+		pumpAmmPool.PoolBaseTokenReserves != 197_000_000_000_000 || pumpAmmPool.PoolQuoteTokenReserves != 85_100_000_000 {
+		t.Fatalf("unexpected pool: %+v", swapInfo.PoolData.Data)
+	}
+}
+
+func TestRouterSwapDirection(t *testing.T) {
+	user, router, authority, pool := newKey(), newKey(), newKey(), newKey()
+	tokenA, tokenB := newKey(), newKey()
+	userA, userB, vaultA, vaultB := newKey(), newKey(), newKey(), newKey()
+	// above the int64 range
+	amountIn := uint64(1<<63 + 5)
+
+	// the router pays the output out first and moves the user's input as its delegate
+	f := newTxFixture(user)
+	route := f.instruction(router, []solana.PublicKey{user, authority, pool}, []byte{1})
+	f.tokenAccount(userA, tokenA, user, 6, amountIn, 0)
+	f.tokenAccount(userB, tokenB, user, 9, 0, 2_000_000_000)
+	f.tokenAccount(vaultA, tokenA, pool, 6, 0, amountIn)
+	f.tokenAccount(vaultB, tokenB, pool, 9, 2_000_000_000, 0)
+	f.transfer(route, 2, vaultB, tokenB, userB, pool, 2_000_000_000, 9)
+	f.transfer(route, 2, userA, tokenA, vaultA, authority, amountIn, 6)
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(tokenA) || swapInfo.TokenInAmount != amountIn || swapInfo.TokenInDecimals != 6 ||
+		!swapInfo.TokenOutMint.Equals(tokenB) || swapInfo.TokenOutAmount != 2_000_000_000 || swapInfo.TokenOutDecimals != 9 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+}
+
+func TestPropAmmSwap(t *testing.T) {
+	user, pair := newKey(), newKey()
+	x, y := newKey(), newKey()
+	userX, userY, reserveX, reserveY := newKey(), newKey(), newKey(), newKey()
+
+	// an Obric swap from y to x, the second vault of the pair
+	f := newTxFixture(user)
+	swap := f.instruction(solanaswapgo.OBRIC_V2_PROGRAM_ID,
+		[]solana.PublicKey{pair, x, y, reserveX, reserveY, userX, userY, newKey(), newKey(), user, solana.TokenProgramID},
+		borsh(solanaswapgo.AnchorSwapDiscriminator[:], false, uint64(5_000_000), uint64(24_000_000)))
+	f.tokenAccount(userX, x, user, 8, 0, 25_000_000)
+	f.tokenAccount(userY, y, user, 6, 5_000_000, 0)
+	f.transfer(swap, 2, userY, y, reserveY, user, 5_000_000, 6)
+	f.transfer(swap, 2, reserveX, x, userX, pair, 25_000_000, 8)
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(y) || swapInfo.TokenInAmount != 5_000_000 || swapInfo.TokenInDecimals != 6 ||
+		!swapInfo.TokenOutMint.Equals(x) || swapInfo.TokenOutAmount != 25_000_000 || swapInfo.TokenOutDecimals != 8 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	obric, ok := swapInfo.Legs[0].Data.(*solanaswapgo.PropAmmSwap)
+	if !ok || obric.AMM != solanaswapgo.OBRIC || obric.AToB || !obric.Pool.Equals(pair) || obric.AmountIn != 5_000_000 ||
+		obric.MinAmountOut != 24_000_000 || obric.User != user.String() {
+		t.Fatalf("unexpected Obric swap: %+v", swapInfo.Legs[0].Data)
+	}
+}
+
+func TestPropAmmRoute(t *testing.T) {
+	user, pool, pair := newKey(), newKey(), newKey()
+	tokenA, tokenB, tokenC := newKey(), newKey(), newKey()
+	userA, userB, userC := newKey(), newKey(), newKey()
+	vaultA, vaultB, zeroFiB, zeroFiC := newKey(), newKey(), newKey(), newKey()
+	router := solana.MustPublicKeyFromBase58("BANANAjs7FJiPQqJTGFzkZJndT9o7UmKiYYGaJz6frGu")
+
+	f := newTxFixture(user)
+	route := f.instruction(router, []solana.PublicKey{user}, []byte{1})
+	f.tokenAccount(userA, tokenA, user, 6, 1_000_000, 0)
+	f.tokenAccount(userB, tokenB, user, 9, 0, 0)
+	f.tokenAccount(userC, tokenC, user, 6, 0, 3_000_000)
+	// a HumidiFi instruction without transfers ahead of the swap on the same pool
+	humidiFi := []solana.PublicKey{user, pool, vaultA, vaultB, userA, userB}
+	f.cpi(route, 2, solanaswapgo.HUMIDIFI_PROGRAM_ID, humidiFi, []byte{9})
+	f.cpi(route, 2, solanaswapgo.HUMIDIFI_PROGRAM_ID, humidiFi, []byte{3})
+	f.transfer(route, 3, userA, tokenA, vaultA, user, 1_000_000, 6)
+	f.transfer(route, 3, vaultB, tokenB, userB, pool, 2_000_000_000, 9)
+	f.cpi(route, 2, solanaswapgo.ZEROFI_PROGRAM_ID, []solana.PublicKey{pair, newKey(), zeroFiB, newKey(), zeroFiC, userB, userC, user},
+		borsh(uint8(6), uint64(2_000_000_000), uint64(2_900_000)))
+	f.transfer(route, 3, userB, tokenB, zeroFiB, user, 2_000_000_000, 9)
+	f.transfer(route, 3, zeroFiC, tokenC, userC, pair, 3_000_000, 6)
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(tokenA) || swapInfo.TokenInAmount != 1_000_000 ||
+		!swapInfo.TokenOutMint.Equals(tokenC) || swapInfo.TokenOutAmount != 3_000_000 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	if len(swapInfo.Legs) != 2 {
+		t.Fatalf("unexpected legs: %+v", swapInfo.Legs)
+	}
+	humidiFiSwap, ok := swapInfo.Legs[0].Data.(*solanaswapgo.PropAmmSwap)
+	if !ok || humidiFiSwap.AMM != solanaswapgo.HUMIDIFI || !humidiFiSwap.AToB || !humidiFiSwap.TokenOutMint.Equals(tokenB) ||
+		humidiFiSwap.TokenOutAmount != 2_000_000_000 {
+		t.Fatalf("unexpected HumidiFi leg: %+v", swapInfo.Legs[0].Data)
+	}
+	zeroFiSwap, ok := swapInfo.Legs[1].Data.(*solanaswapgo.PropAmmSwap)
+	if !ok || zeroFiSwap.AMM != solanaswapgo.ZEROFI || zeroFiSwap.AmountIn != 2_000_000_000 || zeroFiSwap.MinAmountOut != 2_900_000 ||
+		!zeroFiSwap.TokenInMint.Equals(tokenB) || !zeroFiSwap.TokenOutMint.Equals(tokenC) || zeroFiSwap.TokenOutAmount != 3_000_000 {
+		t.Fatalf("unexpected ZeroFi leg: %+v", swapInfo.Legs[1].Data)
+	}
+}
+
+func TestSanctumSwaps(t *testing.T) {
+	user, pool, lst := newKey(), newKey(), newKey()
+	userLST, managerFee, referrer, reserve := newKey(), newKey(), newKey(), newKey()
+	mintTo := func(f *txFixture, outer uint16, destination solana.PublicKey, amount uint64) {
+		f.cpi(outer, 2, solana.TokenProgramID, []solana.PublicKey{lst, destination, newKey()}, borsh(uint8(7), amount))
+	}
+
+	// DepositSol mints the user share, then the manager and referral fees
+	deposit := newTxFixture(user)
+	outer := deposit.instruction(solanaswapgo.SPL_STAKE_POOL_PROGRAM_ID,
+		[]solana.PublicKey{pool, newKey(), reserve, user, userLST, managerFee, referrer, lst, solana.SystemProgramID, solana.TokenProgramID},
+		borsh(uint8(solanaswapgo.STAKE_POOL_DEPOSIT_SOL_INSTRUCTION), uint64(1_000_000_000)))
+	deposit.tokenAccount(userLST, lst, user, 9, 0, 870_000_000)
+	deposit.cpi(outer, 2, solana.SystemProgramID, []solana.PublicKey{user, reserve}, borsh(uint32(2), uint64(1_000_000_000)))
+	mintTo(deposit, outer, userLST, 870_000_000)
+	mintTo(deposit, outer, managerFee, 1_000_000)
+	mintTo(deposit, outer, referrer, 500_000)
+
+	swapInfo := deposit.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID) || swapInfo.TokenInAmount != 1_000_000_000 ||
+		!swapInfo.TokenOutMint.Equals(lst) || swapInfo.TokenOutAmount != 870_000_000 || swapInfo.TokenOutDecimals != 9 {
+		t.Fatalf("unexpected deposit: %+v", swapInfo)
+	}
+	if len(swapInfo.Fees) != 2 || swapInfo.Fees[0].Type != "deposit" || swapInfo.Fees[0].Amount != 1_000_000 ||
+		swapInfo.Fees[1].Type != "referrer" || swapInfo.Fees[1].Amount != 500_000 {
+		t.Fatalf("unexpected deposit fees: %+v", swapInfo.Fees)
+	}
+
+	// an Infinity swap between two LSTs pays its protocol fee from the destination reserves
+	other, userOther, srcReserves, dstReserves, accumulator := newKey(), newKey(), newKey(), newKey(), newKey()
+	swap := newTxFixture(user)
+	outer = swap.instruction(solanaswapgo.SANCTUM_INFINITY_PROGRAM_ID,
+		[]solana.PublicKey{user, lst, other, userLST, userOther, accumulator, solana.TokenProgramID, solana.TokenProgramID, pool, newKey(), srcReserves, dstReserves},
+		borsh(uint8(solanaswapgo.INFINITY_SWAP_EXACT_IN_INSTRUCTION), uint64(870_000_000)))
+	swap.tokenAccount(userLST, lst, user, 9, 870_000_000, 0)
+	swap.tokenAccount(userOther, other, user, 9, 0, 860_000_000)
+	swap.transfer(outer, 2, userLST, lst, srcReserves, user, 870_000_000, 9)
+	swap.transfer(outer, 2, dstReserves, other, userOther, pool, 860_000_000, 9)
+	swap.transfer(outer, 2, dstReserves, other, accumulator, pool, 90_000, 9)
+
+	swapInfo = swap.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(lst) || swapInfo.TokenInAmount != 870_000_000 ||
+		!swapInfo.TokenOutMint.Equals(other) || swapInfo.TokenOutAmount != 860_000_000 {
+		t.Fatalf("unexpected Infinity swap: %+v", swapInfo)
+	}
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "protocol" || !swapInfo.Fees[0].Mint.Equals(other) || swapInfo.Fees[0].Amount != 90_000 {
+		t.Fatalf("unexpected Infinity fees: %+v", swapInfo.Fees)
+	}
+}
+
+func TestStableSwaps(t *testing.T) {
+	user, pool := newKey(), newKey()
+	usdc, usdt := newKey(), newKey()
+	userUSDC, userUSDT, poolUSDC, poolUSDT := newKey(), newKey(), newKey(), newKey()
+
+	// Mercurial passes the user accounts after a variable number of pool token accounts
+	mercurial := newTxFixture(user)
+	outer := mercurial.instruction(solanaswapgo.MERCURIAL_PROGRAM_ID,
+		[]solana.PublicKey{pool, solana.TokenProgramID, newKey(), user, poolUSDC, poolUSDT, newKey(), userUSDT, userUSDC},
+		borsh(uint8(4), uint64(2_000_000), uint64(1_990_000)))
+	mercurial.tokenAccount(userUSDT, usdt, user, 6, 2_000_000, 0)
+	mercurial.tokenAccount(userUSDC, usdc, user, 6, 0, 1_999_000)
+	mercurial.transfer(outer, 2, userUSDT, usdt, poolUSDT, user, 2_000_000, 6)
+	mercurial.transfer(outer, 2, poolUSDC, usdc, userUSDC, pool, 1_999_000, 6)
+
+	swapInfo := mercurial.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(usdt) || swapInfo.TokenInAmount != 2_000_000 ||
+		!swapInfo.TokenOutMint.Equals(usdc) || swapInfo.TokenOutAmount != 1_999_000 {
+		t.Fatalf("unexpected Mercurial swap: %+v", swapInfo)
+	}
+	swap, ok := swapInfo.Legs[0].Data.(*solanaswapgo.StableSwap)
+	if !ok || swap.AMM != solanaswapgo.MERCURIAL || swap.AmountIn != 2_000_000 || swap.MinAmountOut != 1_990_000 ||
+		!swap.ReserveIn.Equals(poolUSDT) || !swap.ReserveOut.Equals(poolUSDC) || len(swapInfo.Fees) != 0 {
+		t.Fatalf("unexpected Mercurial leg: %+v, fees %+v", swapInfo.Legs[0].Data, swapInfo.Fees)
+	}
+
+	// Stabble pays the admin fee to the beneficiary after the user transfer
+	beneficiary := newKey()
+	stabble := newTxFixture(user)
+	outer = stabble.instruction(solanaswapgo.STABBLE_STABLE_SWAP_PROGRAM_ID,
+		[]solana.PublicKey{user, userUSDC, userUSDT, poolUSDC, poolUSDT, beneficiary, pool, newKey(), newKey(), solana.TokenProgramID},
+		borsh(solanaswapgo.AnchorSwapDiscriminator[:], uint8(1), uint64(1_000_000), uint64(995_000)))
+	stabble.tokenAccount(userUSDC, usdc, user, 6, 1_000_000, 0)
+	stabble.tokenAccount(userUSDT, usdt, user, 6, 0, 999_500)
+	stabble.transfer(outer, 2, userUSDC, usdc, poolUSDC, user, 1_000_000, 6)
+	stabble.transfer(outer, 2, poolUSDT, usdt, userUSDT, pool, 999_500, 6)
+	stabble.transfer(outer, 2, poolUSDT, usdt, beneficiary, pool, 50, 6)
+
+	swapInfo = stabble.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(usdc) || swapInfo.TokenInAmount != 1_000_000 ||
+		!swapInfo.TokenOutMint.Equals(usdt) || swapInfo.TokenOutAmount != 999_500 {
+		t.Fatalf("unexpected Stabble swap: %+v", swapInfo)
+	}
+	swap, ok = swapInfo.Legs[0].Data.(*solanaswapgo.StableSwap)
+	if !ok || swap.AMM != solanaswapgo.STABBLE || swap.AmountIn != 1_000_000 || swap.MinAmountOut != 995_000 {
+		t.Fatalf("unexpected Stabble leg: %+v", swapInfo.Legs[0].Data)
+	}
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "admin" || !swapInfo.Fees[0].Mint.Equals(usdt) ||
+		swapInfo.Fees[0].Amount != 50 || !swapInfo.Fees[0].Recipient.Equals(beneficiary) {
+		t.Fatalf("unexpected Stabble fees: %+v", swapInfo.Fees)
+	}
+}
+
+func TestStableSwapRoute(t *testing.T) {
+	user, swap, admin := newKey(), newKey(), newKey()
+	usdc, usdt := newKey(), newKey()
+	userUSDC, userUSDT, poolUSDC, poolUSDT := newKey(), newKey(), newKey(), newKey()
+	router := solana.MustPublicKeyFromBase58("BANANAjs7FJiPQqJTGFzkZJndT9o7UmKiYYGaJz6frGu")
+
+	// the route splits its input over two swaps on the same Saber pool, only the second pays an admin fee
+	f := newTxFixture(user)
+	route := f.instruction(router, []solana.PublicKey{user}, []byte{1})
+	f.tokenAccount(userUSDC, usdc, user, 6, 3_000_000, 0)
+	f.tokenAccount(userUSDT, usdt, user, 6, 0, 2_997_000)
+	saber := []solana.PublicKey{swap, newKey(), user, userUSDC, poolUSDC, poolUSDT, userUSDT, admin, solana.TokenProgramID}
+	f.cpi(route, 2, solanaswapgo.SABER_STABLE_SWAP_PROGRAM_ID, saber, borsh(uint8(1), uint64(1_000_000), uint64(990_000)))
+	f.transfer(route, 3, userUSDC, usdc, poolUSDC, user, 1_000_000, 6)
+	f.transfer(route, 3, poolUSDT, usdt, userUSDT, swap, 999_000, 6)
+	f.cpi(route, 2, solanaswapgo.SABER_STABLE_SWAP_PROGRAM_ID, saber, borsh(uint8(1), uint64(2_000_000), uint64(1_980_000)))
+	f.transfer(route, 3, userUSDC, usdc, poolUSDC, user, 2_000_000, 6)
+	f.transfer(route, 3, poolUSDT, usdt, userUSDT, swap, 1_998_000, 6)
+	f.transfer(route, 3, poolUSDT, usdt, admin, swap, 400, 6)
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(usdc) || swapInfo.TokenInAmount != 3_000_000 ||
+		!swapInfo.TokenOutMint.Equals(usdt) || swapInfo.TokenOutAmount != 2_997_000 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	if len(swapInfo.Legs) != 2 {
+		t.Fatalf("unexpected legs: %+v", swapInfo.Legs)
+	}
+	first, ok := swapInfo.Legs[0].Data.(*solanaswapgo.StableSwap)
+	if !ok || first.AMM != solanaswapgo.SABER || first.AmountIn != 1_000_000 || first.MinAmountOut != 990_000 ||
+		first.TokenOutAmount != 999_000 || !first.ReserveIn.Equals(poolUSDC) || first.AdminFee != 0 {
+		t.Fatalf("unexpected first leg: %+v", swapInfo.Legs[0].Data)
+	}
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "admin" || !swapInfo.Fees[0].Mint.Equals(usdt) ||
+		swapInfo.Fees[0].Amount != 400 || !swapInfo.Fees[0].Recipient.Equals(admin) {
+		t.Fatalf("unexpected fees: %+v", swapInfo.Fees)
+	}
+}
+
+func TestPhoenixRoute(t *testing.T) {
+	user, market, logAuthority := newKey(), newKey(), newKey()
+	sol, usdc := newKey(), newKey()
+	userSOL, userUSDC, baseVault, quoteVault := newKey(), newKey(), newKey(), newKey()
+	router := solana.MustPublicKeyFromBase58("BANANAjs7FJiPQqJTGFzkZJndT9o7UmKiYYGaJz6frGu")
+
+	f := newTxFixture(user)
+	route := f.instruction(router, []solana.PublicKey{user}, []byte{1})
+	f.tokenAccount(userSOL, sol, user, 9, 0, 100_000)
+	f.tokenAccount(userUSDC, usdc, user, 6, 10_000, 4_990)
+	f.tokenAccount(baseVault, sol, market, 9, 1_000_000, 900_000)
+	f.tokenAccount(quoteVault, usdc, market, 6, 0, 5_010)
+	phoenix := []solana.PublicKey{solanaswapgo.PHOENIX_PROGRAM_ID, logAuthority, market, user, userSOL, userUSDC, baseVault, quoteVault, solana.TokenProgramID}
+	tokenTransfer := func(source, destination, authority solana.PublicKey, amount uint64) {
+		f.cpi(route, 3, solana.TokenProgramID, []solana.PublicKey{source, destination, authority}, borsh(uint8(3), amount))
+	}
+
+	// a bid that did not fill ahead of the one that did
+	f.cpi(route, 2, solanaswapgo.PHOENIX_PROGRAM_ID, phoenix, []byte{solanaswapgo.PHOENIX_SWAP_INSTRUCTION, 2, byte(solanaswapgo.PhoenixSideBid)})
+	f.cpi(route, 2, solanaswapgo.PHOENIX_PROGRAM_ID, phoenix, []byte{solanaswapgo.PHOENIX_SWAP_INSTRUCTION, 2, byte(solanaswapgo.PhoenixSideBid)})
+	f.cpi(route, 3, solanaswapgo.PHOENIX_PROGRAM_ID, []solana.PublicKey{logAuthority}, borsh(
+		// header: instruction, sequence number, timestamp, slot, market, signer, total events
+		uint8(solanaswapgo.PHOENIX_LOG_INSTRUCTION), uint8(1), uint8(0), uint64(7), int64(1_700_000_000), uint64(250_000_000), market, user, uint16(2),
+		// fill: index, maker, order sequence number, price in ticks, base lots filled, base lots remaining
+		uint8(2), uint16(0), newKey(), uint64(3), uint64(5), uint64(100), uint64(0),
+		// fill summary: index, client order id, base lots, quote lots, fee in quote lots
+		uint8(6), uint16(1), make([]byte, 16), uint64(100), uint64(500), uint64(1)))
+	tokenTransfer(userUSDC, quoteVault, user, 5_010)
+	tokenTransfer(baseVault, userSOL, market, 100_000)
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(usdc) || swapInfo.TokenInAmount != 5_010 ||
+		!swapInfo.TokenOutMint.Equals(sol) || swapInfo.TokenOutAmount != 100_000 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	if len(swapInfo.Legs) != 1 {
+		t.Fatalf("unexpected legs: %+v", swapInfo.Legs)
+	}
+	swap, ok := swapInfo.Legs[0].Data.(*solanaswapgo.PhoenixSwap)
+	if !ok || swap.Side != solanaswapgo.PhoenixSideBid || swap.SequenceNumber != 7 || swap.BaseLotSize != 1_000 ||
+		swap.QuoteLotSize != 10 || len(swap.Fills) != 1 || swap.Fills[0].BaseAtoms != 100_000 {
+		t.Fatalf("unexpected Phoenix swap: %+v", swapInfo.Legs[0].Data)
+	}
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "taker" || !swapInfo.Fees[0].Mint.Equals(usdc) || swapInfo.Fees[0].Amount != 10 {
+		t.Fatalf("unexpected fees: %+v", swapInfo.Fees)
+	}
+}
+
+func TestMoonshotTrades(t *testing.T) {
+	user, curve, curveTokens, mint, userTokens := newKey(), newKey(), newKey(), newKey(), newKey()
+	accounts := []solana.PublicKey{user, userTokens, curve, curveTokens, newKey(), newKey(), mint, newKey(),
+		solana.TokenProgramID, solana.SPLAssociatedTokenAccountProgramID, solana.SystemProgramID}
+	trade := func(discriminator [8]byte, tokens, collateral uint64) []byte {
+		// token amount, collateral amount, fixed side, slippage bps
+		return borsh(discriminator[:], tokens, collateral, uint8(0), uint64(100))
+	}
+
+	// the TradeEvent of the logs gives the amounts of a buy
+	buy := newTxFixture(user)
+	buy.instruction(solanaswapgo.MOONSHOT_PROGRAM_ID, accounts, trade(solanaswapgo.MOONSHOT_BUY_INSTRUCTION, 1, 1))
+	buy.tokenAccount(userTokens, mint, user, 9, 0, 40_000_000_000)
+	event := borsh(solanaswapgo.MoonshotTradeEventDiscriminator[:], uint64(40_000_000_000), uint64(1_000_000_000),
+		uint64(10_000_000), uint64(2_000_000), uint64(800_000_000_000_000), curve, solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID, user, uint8(0), "")
+	buy.logs = []string{
+		"Program " + solanaswapgo.MOONSHOT_PROGRAM_ID.String() + " invoke [1]",
+		"Program data: " + base64.StdEncoding.EncodeToString(event),
+		"Program " + solanaswapgo.MOONSHOT_PROGRAM_ID.String() + " success",
+	}
+	swapInfo := buy.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID) || swapInfo.TokenInAmount != 1_000_000_000 ||
+		!swapInfo.TokenOutMint.Equals(mint) || swapInfo.TokenOutAmount != 40_000_000_000 || swapInfo.TokenOutDecimals != 9 {
+		t.Fatalf("unexpected buy: %+v", swapInfo)
+	}
+	pool, ok := swapInfo.PoolData.Data.(*solanaswapgo.MoonshotPool)
+	if !ok || !pool.CurveAccount.Equals(curve) || pool.Allocation != 800_000_000_000_000 {
+		t.Fatalf("unexpected pool: %+v", swapInfo.PoolData)
+	}
+
+	// without logs a sell falls back to the balance changes of the signer
+	sell := newTxFixture(user)
+	sell.instruction(solanaswapgo.MOONSHOT_PROGRAM_ID, accounts, trade(solanaswapgo.MOONSHOT_SELL_INSTRUCTION, 1, 1))
+	sell.tokenAccount(userTokens, mint, user, 9, 40_000_000_000, 0)
+	sell.solDelta[0] = 950_000_000
+	swapInfo = sell.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(mint) || swapInfo.TokenInAmount != 40_000_000_000 ||
+		!swapInfo.TokenOutMint.Equals(solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID) || swapInfo.TokenOutAmount != 950_000_000 {
+		t.Fatalf("unexpected sell: %+v", swapInfo)
+	}
+}
+
+func TestOpenbookTakeOrder(t *testing.T) {
+	user, market, maker := newKey(), newKey(), newKey()
+	sol, usdc := newKey(), newKey()
+	baseVault, quoteVault, userSOL, userUSDC := newKey(), newKey(), newKey(), newKey()
+
+	// an ask taking two fills from the same maker
+	f := newTxFixture(user)
+	f.instruction(solanaswapgo.OPENBOOK_V2_PROGRAM_ID,
+		[]solana.PublicKey{user, user, market, newKey(), newKey(), newKey(), baseVault, quoteVault, newKey(), userSOL, userUSDC},
+		borsh(solanaswapgo.OpenbookPlaceTakeOrderDiscriminator[:], uint8(solanaswapgo.OpenbookSideAsk), int64(140), int64(20), int64(1<<40), uint8(3), uint8(10)))
+	f.tokenAccount(baseVault, sol, market, 9, 0, 2_000_000_000)
+	f.tokenAccount(quoteVault, usdc, market, 6, 290_000_000, 10_000_000)
+	fill := func(seqNum uint64, price, quantity int64) []byte {
+		return borsh(solanaswapgo.OpenbookFillLogDiscriminator[:], market, uint8(solanaswapgo.OpenbookSideAsk), uint8(0), false,
+			uint64(1_700_000_000), seqNum, maker, uint64(42), uint64(0), uint64(1_699_999_000), user, uint64(0), uint64(70_000), price, quantity)
+	}
+	total := borsh(solanaswapgo.OpenbookTotalOrderFillEventDiscriminator[:], uint8(solanaswapgo.OpenbookSideAsk), user,
+		uint64(2_000_000_000), uint64(279_860_000), uint64(140_000))
+	program := solanaswapgo.OPENBOOK_V2_PROGRAM_ID.String()
+	f.logs = []string{
+		"Program " + program + " invoke [1]",
+		"Program data: " + base64.StdEncoding.EncodeToString(fill(1, 141, 10)),
+		"Program data: " + base64.StdEncoding.EncodeToString(fill(2, 140, 10)),
+		"Program data: " + base64.StdEncoding.EncodeToString(total),
+		"Program " + program + " success",
+	}
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(sol) || swapInfo.TokenInAmount != 2_000_000_000 || swapInfo.TokenInDecimals != 9 ||
+		!swapInfo.TokenOutMint.Equals(usdc) || swapInfo.TokenOutAmount != 279_860_000 || swapInfo.TokenOutDecimals != 6 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	swap, ok := swapInfo.Legs[0].Data.(*solanaswapgo.OpenbookSwap)
+	if !ok || swap.Instruction != "place_take_order" || swap.Side != solanaswapgo.OpenbookSideAsk || len(swap.Fills) != 2 ||
+		swap.Fills[0].PriceLots != 141 || swap.Fills[1].BaseLots != 10 || len(swap.Makers) != 1 || !swap.Makers[0].Equals(maker) {
+		t.Fatalf("unexpected OpenBook swap: %+v", swapInfo.Legs[0].Data)
+	}
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "taker" || !swapInfo.Fees[0].Mint.Equals(usdc) || swapInfo.Fees[0].Amount != 140_000 {
+		t.Fatalf("unexpected fees: %+v", swapInfo.Fees)
+	}
+}
+
+func TestJupiterRoute(t *testing.T) {
+	user, platform := newKey(), newKey()
+	tokenA, tokenB, tokenC := newKey(), newKey(), newKey()
+	// route plan length, then in amount, quoted out amount, slippage bps, platform fee bps
+	routeArgs := func(discriminator [8]byte, inAmount, quotedOut uint64) []byte {
+		return borsh(discriminator[:], uint8(1), uint32(0), inAmount, quotedOut, uint16(50), uint8(20))
+	}
+
+	// a shared accounts route reporting its two hops with a SwapsEvent and its platform fee with a FeeEvent
+	f := newTxFixture(user)
+	route := f.instruction(solanaswapgo.JUPITER_PROGRAM_ID, []solana.PublicKey{solana.TokenProgramID, user},
+		routeArgs(solanaswapgo.JupiterSharedRouteDiscriminator, 1_000_000, 3_000_000))
+	f.tokenAccount(newKey(), tokenA, user, 6, 1_000_000, 0)
+	f.tokenAccount(newKey(), tokenB, user, 9, 0, 0)
+	f.tokenAccount(newKey(), tokenC, user, 8, 0, 2_990_000)
+	f.cpi(route, 2, solanaswapgo.JUPITER_PROGRAM_ID, nil, borsh(solanaswapgo.JupiterSwapsEventDiscriminator[:], uint32(2),
+		tokenA, uint64(1_000_000), tokenB, uint64(2_000_000_000),
+		tokenB, uint64(2_000_000_000), tokenC, uint64(2_990_000)))
+	f.cpi(route, 2, solanaswapgo.JUPITER_PROGRAM_ID, nil, borsh(solanaswapgo.JupiterFeeEventDiscriminator[:], platform, tokenC, uint64(6_000)))
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(tokenA) || swapInfo.TokenInAmount != 1_000_000 || swapInfo.TokenInDecimals != 6 ||
+		!swapInfo.TokenOutMint.Equals(tokenC) || swapInfo.TokenOutAmount != 2_990_000 || swapInfo.TokenOutDecimals != 8 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	if len(swapInfo.Legs) != 2 || !swapInfo.Legs[0].TokenOutMint.Equals(tokenB) || swapInfo.Legs[1].TokenInAmount != 2_000_000_000 {
+		t.Fatalf("unexpected legs: %+v", swapInfo.Legs)
+	}
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "platform" || !swapInfo.Fees[0].Recipient.Equals(platform) ||
+		!swapInfo.Fees[0].Mint.Equals(tokenC) || swapInfo.Fees[0].Amount != 6_000 {
+		t.Fatalf("unexpected fees: %+v", swapInfo.Fees)
+	}
+	args, ok := swapInfo.RouteData.Data.(*solanaswapgo.JupiterRouteArgs)
+	if !ok || args.Instruction != "shared_accounts_route" || args.InAmount != 1_000_000 || args.QuotedOutAmount != 3_000_000 ||
+		args.SlippageBps != 50 || args.PlatformFeeBps != 20 {
+		t.Fatalf("unexpected route args: %+v", swapInfo.RouteData)
+	}
+
+	// a split route reports a SwapEvent per split
+	f = newTxFixture(user)
+	route = f.instruction(solanaswapgo.JUPITER_PROGRAM_ID, []solana.PublicKey{user}, routeArgs(solanaswapgo.JupiterRouteDiscriminator, 1_000_000, 1_990_000_000))
+	f.tokenAccount(newKey(), tokenA, user, 6, 1_000_000, 0)
+	f.tokenAccount(newKey(), tokenB, user, 9, 0, 1_995_000_000)
+	for _, amm := range []solana.PublicKey{solanaswapgo.RAYDIUM_V4_PROGRAM_ID, solanaswapgo.ORCA_PROGRAM_ID} {
+		f.cpi(route, 2, solanaswapgo.JUPITER_PROGRAM_ID, nil, borsh(solanaswapgo.JupiterRouteEventDiscriminator[:],
+			amm, tokenA, uint64(500_000), tokenB, uint64(997_500_000)))
+	}
+
+	swapInfo = f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(tokenA) || swapInfo.TokenInAmount != 1_000_000 ||
+		!swapInfo.TokenOutMint.Equals(tokenB) || swapInfo.TokenOutAmount != 1_995_000_000 {
+		t.Fatalf("unexpected split swap: %+v", swapInfo)
+	}
+	if len(swapInfo.Legs) != 2 || !swapInfo.Legs[0].ProgramID.Equals(solanaswapgo.RAYDIUM_V4_PROGRAM_ID) ||
+		!swapInfo.Legs[1].ProgramID.Equals(solanaswapgo.ORCA_PROGRAM_ID) {
+		t.Fatalf("unexpected split legs: %+v", swapInfo.Legs)
+	}
+}
+
+func TestLimitOrderFill(t *testing.T) {
+	keeper, maker, order := newKey(), newKey(), newKey()
+	usdc, token := newKey(), newKey()
+	reserve, makerToken, feeAccount, keeperUSDC, keeperToken := newKey(), newKey(), newKey(), newKey(), newKey()
+	eventIxTag := []byte{228, 69, 165, 46, 81, 203, 154, 29}
+
+	// a keeper flash fills a v2 order selling 100 USDC, paying the maker and the fee account in TOKEN
+	f := newTxFixture(keeper)
+	fill := f.instruction(solanaswapgo.JUPITER_LIMIT_ORDER_V2_PROGRAM_ID,
+		[]solana.PublicKey{keeper, maker, order, reserve, makerToken, feeAccount, usdc, token, solana.TokenProgramID},
+		borsh(solanaswapgo.LimitOrderFlashFillOrderDiscriminator[:], uint64(100_000_000)))
+	f.tokenAccount(reserve, usdc, order, 6, 100_000_000, 0)
+	f.tokenAccount(makerToken, token, maker, 9, 0, 4_990_000_000)
+	f.tokenAccount(feeAccount, token, newKey(), 9, 0, 10_000_000)
+	f.tokenAccount(keeperUSDC, usdc, keeper, 6, 0, 100_000_000)
+	f.tokenAccount(keeperToken, token, keeper, 9, 5_000_000_000, 0)
+	f.transfer(fill, 2, reserve, usdc, keeperUSDC, order, 100_000_000, 6)
+	f.transfer(fill, 2, keeperToken, token, makerToken, keeper, 4_990_000_000, 9)
+	f.transfer(fill, 2, keeperToken, token, feeAccount, keeper, 10_000_000, 9)
+	f.cpi(fill, 2, solanaswapgo.JUPITER_LIMIT_ORDER_V2_PROGRAM_ID, nil, borsh(eventIxTag, solanaswapgo.LimitOrderTradeEventDiscriminator[:],
+		order, keeper, uint64(0), uint64(0), uint64(100_000_000), uint64(5_000_000_000)))
+
+	swapInfo := f.swapInfo(t)
+	if len(swapInfo.Signers) != 1 || !swapInfo.Signers[0].Equals(maker) {
+		t.Fatalf("unexpected signers: %v", swapInfo.Signers)
+	}
+	if !swapInfo.TokenInMint.Equals(usdc) || swapInfo.TokenInAmount != 100_000_000 || swapInfo.TokenInDecimals != 6 ||
+		!swapInfo.TokenOutMint.Equals(token) || swapInfo.TokenOutAmount != 5_000_000_000 || swapInfo.TokenOutDecimals != 9 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "limit_order" || !swapInfo.Fees[0].Mint.Equals(token) ||
+		swapInfo.Fees[0].Amount != 10_000_000 || !swapInfo.Fees[0].Recipient.Equals(feeAccount) {
+		t.Fatalf("unexpected fees: %+v", swapInfo.Fees)
+	}
+	filled, ok := swapInfo.RouteData.Data.(*solanaswapgo.LimitOrderFill)
+	if !ok || filled.Version != 2 || !filled.Order.Equals(order) || !filled.Taker.Equals(keeper) {
+		t.Fatalf("unexpected fill: %+v", swapInfo.RouteData)
+	}
+}
+
+func TestOKXRoute(t *testing.T) {
+	user, commission, pair, cpmmAuthority := newKey(), newKey(), newKey(), newKey()
+	tokenA, tokenB, tokenC := newKey(), newKey(), newKey()
+	userA, userB, userC, commissionA := newKey(), newKey(), newKey(), newKey()
+	solFiA, solFiB, cpmmB, cpmmC := newKey(), newKey(), newKey(), newKey()
+
+	// SolFi from A to B then Raydium CPMM from B to C, with a 0.1% commission taken from the input
+	args := borsh(solanaswapgo.OKX_COMMISSION_SPL_SWAP2_DISCRIMINATOR[:], uint64(1_000_000), uint64(3_000_000), uint64(2_950_000),
+		uint32(1), uint64(1_000_000),
+		// routes: a hop through Solfi then a hop through RaydiumCpmmSwap
+		uint32(2), uint32(1), uint32(1), uint8(31), uint32(1), uint8(100), uint32(1), uint32(1), uint8(14), uint32(1), uint8(100),
+		uint32(1<<31|1_000))
+	f := newTxFixture(user)
+	route := f.instruction(solanaswapgo.OKX_DEX_ROUTER_PROGRAM_ID, []solana.PublicKey{user, userA, userC, tokenA, tokenC, commission}, args)
+	f.tokenAccount(userA, tokenA, user, 6, 1_000_000, 0)
+	f.tokenAccount(userB, tokenB, user, 9, 0, 0)
+	f.tokenAccount(userC, tokenC, user, 6, 0, 2_990_000)
+	f.tokenAccount(commissionA, tokenA, commission, 6, 0, 1_000)
+	f.transfer(route, 2, userA, tokenA, commissionA, user, 1_000, 6)
+	f.cpi(route, 2, solanaswapgo.SOLFI_PROGRAM_ID, []solana.PublicKey{user, pair, solFiA, solFiB, userA, userB}, borsh(uint8(7), uint64(999_000), uint64(0)))
+	f.transfer(route, 3, userA, tokenA, solFiA, user, 999_000, 6)
+	f.transfer(route, 3, solFiB, tokenB, userB, pair, 2_000_000_000, 9)
+	f.cpi(route, 2, solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID, []solana.PublicKey{user, cpmmAuthority, userB, userC, cpmmB, cpmmC}, []byte{1})
+	f.transfer(route, 3, userB, tokenB, cpmmB, user, 2_000_000_000, 9)
+	f.transfer(route, 3, cpmmC, tokenC, userC, cpmmAuthority, 2_990_000, 6)
+
+	swapInfo := f.swapInfo(t)
+	if !swapInfo.TokenInMint.Equals(tokenA) || swapInfo.TokenInAmount != 999_000 ||
+		!swapInfo.TokenOutMint.Equals(tokenC) || swapInfo.TokenOutAmount != 2_990_000 {
+		t.Fatalf("unexpected swap: %+v", swapInfo)
+	}
+	if len(swapInfo.Legs) != 2 || swapInfo.Legs[0].AMM != string(solanaswapgo.SOLFI) || !swapInfo.Legs[0].TokenOutMint.Equals(tokenB) ||
+		!swapInfo.Legs[1].ProgramID.Equals(solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID) || swapInfo.Legs[1].TokenInAmount != 2_000_000_000 {
+		t.Fatalf("unexpected legs: %+v", swapInfo.Legs)
+	}
+	if len(swapInfo.Fees) != 1 || swapInfo.Fees[0].Type != "commission" || !swapInfo.Fees[0].Mint.Equals(tokenA) ||
+		swapInfo.Fees[0].Amount != 1_000 || !swapInfo.Fees[0].Recipient.Equals(commission) {
+		t.Fatalf("unexpected fees: %+v", swapInfo.Fees)
+	}
+	okx, ok := swapInfo.RouteData.Data.(*solanaswapgo.OKXSwap)
+	if !ok || okx.Instruction != "commission_spl_swap2" || okx.CommissionRate != 1_000 || !okx.CommissionFromInput ||
+		len(okx.Hops) != 2 || okx.Hops[0].Dex != "Solfi" || okx.Hops[1].Dex != "RaydiumCpmmSwap" || okx.Args.MinReturn != 2_950_000 {
+		t.Fatalf("unexpected OKX route: %+v", swapInfo.RouteData)
+	}
+}
+
+func TestRouterVenueTransfers(t *testing.T) {
+	user, feeWallet, poolAuthority := newKey(), newKey(), newKey()
+	tokenA, tokenB := newKey(), newKey()
+	userA, userB, feeA, poolA, poolB := newKey(), newKey(), newKey(), newKey(), newKey()
+	router := solana.MustPublicKeyFromBase58("BANANAjs7FJiPQqJTGFzkZJndT9o7UmKiYYGaJz6frGu")
+	raydium := solanaswapgo.RAYDIUM_V4_PROGRAM_ID
+
+	// the router takes its fee in the input mint next to the Raydium swap, stack heights are either recorded
+	// or read from the invoke depths of the logs
+	build := func(heights bool) *txFixture {
+		height := func(h int) int {
+			if heights {
+				return h
+			}
+			return 0
+		}
+		f := newTxFixture(user)
+		route := f.instruction(router, []solana.PublicKey{user}, []byte{1})
+		f.tokenAccount(userA, tokenA, user, 6, 1_005_000, 0)
+		f.tokenAccount(userB, tokenB, user, 9, 0, 2_000_000_000)
+		f.tokenAccount(feeA, tokenA, feeWallet, 6, 0, 5_000)
+		f.transfer(route, height(2), userA, tokenA, feeA, user, 5_000, 6)
+		f.cpi(route, height(2), raydium, []solana.PublicKey{poolAuthority, poolA, poolB}, []byte{9})
+		f.transfer(route, height(3), userA, tokenA, poolA, user, 1_000_000, 6)
+		f.transfer(route, height(3), poolB, tokenB, userB, poolAuthority, 2_000_000_000, 9)
+		if !heights {
+			token := solana.TokenProgramID.String()
+			f.logs = []string{
+				"Program " + router.String() + " invoke [1]",
+				"Program " + token + " invoke [2]",
+				"Program " + token + " success",
+				"Program " + raydium.String() + " invoke [2]",
+				"Program " + token + " invoke [3]",
+				"Program " + token + " success",
+				"Program " + token + " invoke [3]",
+				"Program " + token + " success",
+				"Program " + raydium.String() + " success",
+				"Program " + router.String() + " success",
+			}
+		}
+		return f
+	}
+
+	for _, heights := range []bool{true, false} {
+		f := build(heights)
+		swapInfo := f.swapInfo(t)
+		if !swapInfo.TokenInMint.Equals(tokenA) || swapInfo.TokenInAmount != 1_000_000 ||
+			!swapInfo.TokenOutMint.Equals(tokenB) || swapInfo.TokenOutAmount != 2_000_000_000 {
+			t.Fatalf("unexpected swap with stack heights %v: %+v", heights, swapInfo)
+		}
+	}
+}
+
+func TestInstructionTree(t *testing.T) {
+	user, pool, router := newKey(), newKey(), newKey()
+	mint, userTokens, poolTokens := newKey(), newKey(), newKey()
+	token := solana.TokenProgramID.String()
+
+	// inner instructions without stack heights, the depths come from the logs
+	f := newTxFixture(user)
+	outer := f.instruction(router, []solana.PublicKey{user, pool}, []byte{1})
+	f.cpi(outer, 0, solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID, []solana.PublicKey{pool}, []byte{2})
+	f.transfer(outer, 0, userTokens, mint, poolTokens, user, 1_000, 6)
+	f.transfer(outer, 0, poolTokens, mint, userTokens, pool, 10, 6)
+	f.logs = []string{
+		"Program " + router.String() + " invoke [1]",
+		"Program " + solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID.String() + " invoke [2]",
+		"Program log: Instruction: SwapBaseInput",
+		"Program " + token + " invoke [3]",
+		"Program " + token + " consumed 4645 of 180000 compute units",
+		"Program " + token + " success",
+		"Program " + solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID.String() + " consumed 30000 of 190000 compute units",
+		"Program " + solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID.String() + " success",
+		"Program " + token + " invoke [2]",
+		"Program " + token + " consumed 4645 of 160000 compute units",
+		"Program " + token + " success",
+		"Program " + router.String() + " consumed 50000 of 200000 compute units",
+		"Program " + router.String() + " success",
+	}
+
+	tree := f.parser(t).InstructionTree()
+	if len(tree) != 1 || tree[0].InnerIndex != -1 || tree[0].ComputeUnits != 50_000 || len(tree[0].Children) != 2 {
+		t.Fatalf("unexpected outer instruction: %+v", tree)
+	}
+	cpmm := tree[0].Children[0]
+	if !cpmm.ProgramID.Equals(solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID) || cpmm.StackHeight != 2 || cpmm.ComputeUnits != 30_000 ||
+		len(cpmm.Logs) != 3 || cpmm.Logs[0] != "Program log: Instruction: SwapBaseInput" || len(cpmm.Children) != 1 {
+		t.Fatalf("unexpected CPMM invocation: %+v", cpmm)
+	}
+	if transfer := cpmm.Children[0]; transfer.Name != "transfer_checked" || transfer.StackHeight != 3 || transfer.InnerIndex != 1 ||
+		!transfer.Accounts[2].Equals(poolTokens) {
+		t.Fatalf("unexpected CPMM transfer: %+v", transfer)
+	}
+	if payout := tree[0].Children[1]; payout.StackHeight != 2 || payout.InnerIndex != 2 || len(payout.Children) != 0 {
+		t.Fatalf("unexpected router transfer: %+v", payout)
+	}
+
+	var visited []int
+	tree[0].Walk(func(node *solanaswapgo.InstructionNode) {
+		visited = append(visited, node.InnerIndex)
+	})
+	if fmt.Sprint(visited) != "[-1 0 1 2]" {
+		t.Fatalf("unexpected walk order: %v", visited)
+	}
+}
+
+func TestLaunches(t *testing.T) {
+	eventIxTag := []byte{228, 69, 165, 46, 81, 203, 154, 29}
+	creator, pool, mint, quoteMint := newKey(), newKey(), newKey(), newKey()
+
+	// a Raydium Launchpad initialize followed by the buy of the creator, the trade event gives the reserves
+	raydium := newTxFixture(creator)
+	raydium.instruction(solanaswapgo.RAYDIUM_Launchpad_PROGRAM_ID,
+		[]solana.PublicKey{creator, creator, newKey(), newKey(), newKey(), pool, mint, quoteMint, newKey(), newKey()},
+		borsh(solanaswapgo.RaydiumLaunchpadInitializeDiscriminator[:], uint8(6), "Token", "TKN", "https://example.com/token.json",
+			uint8(0), uint64(1_000_000_000_000_000)))
+	buy := raydium.instruction(solanaswapgo.RAYDIUM_Launchpad_PROGRAM_ID, []solana.PublicKey{creator, pool}, []byte{1})
+	raydium.cpi(buy, 2, solanaswapgo.RAYDIUM_Launchpad_PROGRAM_ID, []solana.PublicKey{newKey()},
+		borsh(eventIxTag, solanaswapgo.RaydiumLaunchpadTradeEventDiscriminator[:], pool, uint64(793_100_000_000_000),
+			uint64(1_073_025_605_596_382), uint64(30_000_852_951), uint64(0), uint64(0), uint64(35_000_000_000_000), uint64(1_000_000_000),
+			uint64(1_000_000_000), uint64(35_000_000_000_000), uint64(2_500_000), uint64(10_000_000), uint64(0)))
+
+	launch, err := raydium.parser(t).ParseTransactionForLaunch()
+	if err != nil {
+		t.Fatalf("failed to parse launch: %s", err)
+	}
+	if launch.Platform != solanaswapgo.RAYDIUM_Launchpad || launch.Instruction != "initialize" || !launch.Mint.Equals(mint) ||
+		!launch.QuoteMint.Equals(quoteMint) || !launch.Pool.Equals(pool) || !launch.Creator.Equals(creator) || launch.Decimals != 6 ||
+		launch.TotalSupply != 1_000_000_000_000_000 || launch.Symbol != "TKN" || launch.Uri != "https://example.com/token.json" ||
+		launch.BaseReserves != 1_073_025_605_596_382 || launch.QuoteReserves != 30_000_852_951 {
+		t.Fatalf("unexpected Raydium Launchpad launch: %+v", launch)
+	}
+	if launch.DevBuy == nil || !launch.DevBuy.Buyer.Equals(creator) || launch.DevBuy.TokenAmount != 35_000_000_000_000 ||
+		launch.DevBuy.QuoteAmount != 1_000_000_000 {
+		t.Fatalf("unexpected Raydium Launchpad dev buy: %+v", launch.DevBuy)
+	}
+
+	// a Meteora DBC pool, only the quote to base swap event of the new pool is its dev buy
+	meteora := newTxFixture(creator)
+	meteora.instruction(solanaswapgo.METEORA_DBC_PROGRAM_ID,
+		[]solana.PublicKey{newKey(), newKey(), creator, mint, solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID, pool, newKey(), newKey()},
+		borsh(solanaswapgo.MeteoraDbcInitializeSplDiscriminator[:], "Token", "TKN", "https://example.com/token.json"))
+	meteora.tokenAccount(newKey(), mint, creator, 6, 0, 25_000_000_000)
+	swapEvent := func(pool solana.PublicKey, direction uint8, amountIn, amountOut uint64) []byte {
+		// pool, config, trade direction, has referral, params, swap result, amount in, current timestamp
+		return borsh(eventIxTag, solanaswapgo.MeteoraDbcSwapEventDiscriminator[:], pool, newKey(), direction, false,
+			amountIn, uint64(0), amountIn, amountOut, uint64(0), uint64(10_000), uint64(0), uint64(0), amountIn, uint64(1_700_000_000))
+	}
+	swap := meteora.instruction(solanaswapgo.METEORA_DBC_PROGRAM_ID, []solana.PublicKey{creator, pool}, []byte{2})
+	meteora.cpi(swap, 2, solanaswapgo.METEORA_DBC_PROGRAM_ID, []solana.PublicKey{newKey()}, swapEvent(newKey(), 1, 7, 7))
+	meteora.cpi(swap, 2, solanaswapgo.METEORA_DBC_PROGRAM_ID, []solana.PublicKey{newKey()}, swapEvent(pool, 0, 5, 5))
+	meteora.cpi(swap, 2, solanaswapgo.METEORA_DBC_PROGRAM_ID, []solana.PublicKey{newKey()}, swapEvent(pool, 1, 500_000_000, 25_000_000_000))
+
+	launch, err = meteora.parser(t).ParseTransactionForLaunch()
+	if err != nil {
+		t.Fatalf("failed to parse launch: %s", err)
+	}
+	if launch.Platform != solanaswapgo.METEORA_DBC || launch.Instruction != "initialize_virtual_pool_with_spl_token" ||
+		!launch.Mint.Equals(mint) || !launch.QuoteMint.Equals(solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID) || !launch.Pool.Equals(pool) ||
+		!launch.Creator.Equals(creator) || launch.Decimals != 6 || launch.Name != "Token" || !launch.TokenProgram.Equals(solana.TokenProgramID) {
+		t.Fatalf("unexpected Meteora DBC launch: %+v", launch)
+	}
+	if launch.DevBuy == nil || launch.DevBuy.TokenAmount != 25_000_000_000 || launch.DevBuy.QuoteAmount != 500_000_000 {
+		t.Fatalf("unexpected Meteora DBC dev buy: %+v", launch.DevBuy)
+	}
+
+	if _, err := newTxFixture(creator).parser(t).ParseTransactionForLaunch(); err == nil {
+		t.Fatalf("expected no launch in an empty transaction")
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	eventIxTag := []byte{228, 69, 165, 46, 81, 203, 154, 29}
+	withdrawAuthority, curve, mint := newKey(), newKey(), newKey()
+	pool, lpMint, baseVault, quoteVault, lpTokens := newKey(), newKey(), newKey(), newKey(), newKey()
+
+	// pump.fun migrate creating the PumpAmm pool, the LP tokens minted to the migration authority are burned
+	pump := newTxFixture(withdrawAuthority)
+	migrate := pump.instruction(solanaswapgo.PUMP_FUN_PROGRAM_ID, []solana.PublicKey{newKey(), withdrawAuthority, mint, curve, newKey()},
+		solanaswapgo.PumpfunMigrateDiscriminator[:])
+	pump.cpi(migrate, 2, solanaswapgo.PUMP_AMM_PROGRAM_ID,
+		[]solana.PublicKey{pool, newKey(), withdrawAuthority, mint, solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID, lpMint, newKey(), newKey(), lpTokens, baseVault, quoteVault},
+		borsh(solanaswapgo.PumpAmmCreatePoolDiscriminator[:], uint16(0), uint64(206_900_000_000_000), uint64(84_990_359_346)))
+	pump.cpi(migrate, 3, solana.TokenProgramID, []solana.PublicKey{lpMint, lpTokens, pool}, borsh(uint8(7), uint64(4_193_388_284_320)))
+	pump.cpi(migrate, 2, solana.TokenProgramID, []solana.PublicKey{lpTokens, lpMint, withdrawAuthority}, borsh(uint8(8), uint64(4_193_388_284_320)))
+	pump.cpi(migrate, 2, solanaswapgo.PUMP_FUN_PROGRAM_ID, []solana.PublicKey{newKey()},
+		borsh(eventIxTag, solanaswapgo.PumpfunCompletePumpAmmMigrationEventDiscriminator[:], withdrawAuthority, mint,
+			uint64(206_900_000_000_000), uint64(84_990_359_346), uint64(15_000_000), curve, int64(1_700_000_000), pool))
+	pump.tokenAccount(baseVault, mint, pool, 6, 0, 206_900_000_000_000)
+	pump.tokenAccount(quoteVault, solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID, pool, 9, 0, 84_990_359_346)
+
+	migration, err := pump.parser(t).ParseTransactionForMigrate()
+	if err != nil {
+		t.Fatalf("failed to parse migration: %s", err)
+	}
+	if migration.Platform != solanaswapgo.PUMP_FUN || migration.Destination != solanaswapgo.PUMP_SWAP || !migration.Curve.Equals(curve) ||
+		!migration.Pool.Equals(pool) || !migration.BaseMint.Equals(mint) || !migration.QuoteMint.Equals(solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID) ||
+		migration.BaseAmount != 206_900_000_000_000 || migration.QuoteAmount != 84_990_359_346 || migration.MigrationFee != 15_000_000 {
+		t.Fatalf("unexpected pump.fun migration: %+v", migration)
+	}
+	if !migration.LpMint.Equals(lpMint) || migration.LpAmount != 4_193_388_284_320 || !migration.LpRecipient.Equals(lpTokens) ||
+		migration.LpBurned != 4_193_388_284_320 {
+		t.Fatalf("unexpected pump.fun LP handling: %+v", migration)
+	}
+
+	// Raydium Launchpad migrate_to_cpswap, CPMM sorts the mints and the launchpad pool tells the base apart
+	quoteMint := newKey()
+	launchpadPool, _, err := solana.FindProgramAddress([][]byte{[]byte("pool"), mint.Bytes(), quoteMint.Bytes()}, solanaswapgo.RAYDIUM_Launchpad_PROGRAM_ID)
+	if err != nil {
+		t.Fatalf("failed to derive the launchpad pool: %s", err)
+	}
+	raydium := newTxFixture(withdrawAuthority)
+	migrate = raydium.instruction(solanaswapgo.RAYDIUM_Launchpad_PROGRAM_ID, []solana.PublicKey{withdrawAuthority, newKey(), launchpadPool},
+		solanaswapgo.RaydiumLaunchpadMigrateToCpswapDiscriminator[:])
+	raydium.cpi(migrate, 2, solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID,
+		[]solana.PublicKey{withdrawAuthority, newKey(), newKey(), pool, quoteMint, mint, lpMint, newKey(), newKey(), lpTokens, quoteVault, baseVault},
+		borsh(solanaswapgo.RaydiumCPMMInitializeDiscriminator[:], uint64(85_000_000_000), uint64(200_000_000_000_000), uint64(0)))
+	raydium.cpi(migrate, 3, solana.TokenProgramID, []solana.PublicKey{lpMint, lpTokens, newKey()}, borsh(uint8(7), uint64(4_000_000_000_000)))
+	raydium.tokenAccount(baseVault, mint, pool, 6, 0, 200_000_000_000_000)
+	raydium.tokenAccount(quoteVault, quoteMint, pool, 9, 0, 85_000_000_000)
+
+	migration, err = raydium.parser(t).ParseTransactionForMigrate()
+	if err != nil {
+		t.Fatalf("failed to parse migration: %s", err)
+	}
+	if migration.Platform != solanaswapgo.RAYDIUM_Launchpad || migration.Destination != solanaswapgo.RAYDIUM_CPMM ||
+		!migration.Curve.Equals(launchpadPool) || !migration.Pool.Equals(pool) || !migration.BaseMint.Equals(mint) ||
+		!migration.QuoteMint.Equals(quoteMint) || migration.BaseAmount != 200_000_000_000_000 || migration.QuoteAmount != 85_000_000_000 {
+		t.Fatalf("unexpected Raydium Launchpad migration: %+v", migration)
+	}
+	if migration.LpAmount != 4_000_000_000_000 || !migration.LpRecipient.Equals(lpTokens) || migration.LpBurned != 0 {
+		t.Fatalf("unexpected Raydium Launchpad LP handling: %+v", migration)
+	}
+
+	if _, err := newTxFixture(withdrawAuthority).parser(t).ParseTransactionForMigrate(); err == nil {
+		t.Fatalf("expected no migration in an empty transaction")
+	}
+}
+
+// txFixture builds an offline transaction, the first key added is the signer
+type txFixture struct {
+	keys     solana.PublicKeySlice
+	outer    []solana.CompiledInstruction
+	inner    map[uint16][]rpc.CompiledInstruction
+	pre      []rpc.TokenBalance
+	post     []rpc.TokenBalance
+	logs     []string
+	solDelta map[uint16]int64
+}
+
+func newTxFixture(signer solana.PublicKey) *txFixture {
+	f := &txFixture{inner: make(map[uint16][]rpc.CompiledInstruction), solDelta: make(map[uint16]int64)}
+	f.key(signer)
+	return f
+}
+
+// key returns the index of an account, adding it when it is new
+func (f *txFixture) key(account solana.PublicKey) uint16 {
+	for i, key := range f.keys {
+		if key.Equals(account) {
+			return uint16(i)
+		}
+	}
+	f.keys = append(f.keys, account)
+	return uint16(len(f.keys) - 1)
+}
+
+func (f *txFixture) compile(programID solana.PublicKey, accounts []solana.PublicKey) (uint16, []uint16) {
+	indexes := make([]uint16, len(accounts))
+	for i, account := range accounts {
+		indexes[i] = f.key(account)
+	}
+	return f.key(programID), indexes
+}
+
+// instruction adds an outer instruction and returns its index
+func (f *txFixture) instruction(programID solana.PublicKey, accounts []solana.PublicKey, data []byte) uint16 {
+	program, indexes := f.compile(programID, accounts)
+	f.outer = append(f.outer, solana.CompiledInstruction{ProgramIDIndex: program, Accounts: indexes, Data: data})
+	return uint16(len(f.outer) - 1)
+}
+
+// cpi adds an inner instruction to the outer instruction at index, stackHeight 2 is called by the outer instruction
+func (f *txFixture) cpi(outer uint16, stackHeight int, programID solana.PublicKey, accounts []solana.PublicKey, data []byte) {
+	program, indexes := f.compile(programID, accounts)
+	f.inner[outer] = append(f.inner[outer], rpc.CompiledInstruction{ProgramIDIndex: program, Accounts: indexes, Data: data, StackHeight: uint16(stackHeight)})
+}
+
+// tokenAccount records the mint and owner of a token account, with its balance before and after
+func (f *txFixture) tokenAccount(account, mint, owner solana.PublicKey, decimals uint8, pre, post uint64) {
+	index := f.key(account)
+	balance := func(amount uint64) rpc.TokenBalance {
+		return rpc.TokenBalance{AccountIndex: index, Mint: mint, Owner: &owner, UiTokenAmount: &rpc.UiTokenAmount{Amount: fmt.Sprint(amount), Decimals: decimals}}
+	}
+	f.pre, f.post = append(f.pre, balance(pre)), append(f.post, balance(post))
+}
+
+func (f *txFixture) parser(t *testing.T) *solanaswapgo.Parser {
+	t.Helper()
+	meta := &rpc.TransactionMeta{PreTokenBalances: f.pre, PostTokenBalances: f.post, LogMessages: f.logs}
+	for i := range f.outer {
+		if inner := f.inner[uint16(i)]; len(inner) > 0 {
+			meta.InnerInstructions = append(meta.InnerInstructions, rpc.InnerInstruction{Index: uint16(i), Instructions: inner})
+		}
+	}
+	for i := range f.keys {
+		pre := uint64(10_000_000_000)
+		meta.PreBalances = append(meta.PreBalances, pre)
+		meta.PostBalances = append(meta.PostBalances, uint64(int64(pre)+f.solDelta[uint16(i)]))
+	}
+	tx := &solana.Transaction{Message: solana.Message{
+		Header:       solana.MessageHeader{NumRequiredSignatures: 1},
+		AccountKeys:  f.keys,
+		Instructions: f.outer,
+	}}
+	parser, err := solanaswapgo.NewTransactionParserFromTransaction(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to create parser: %s", err)
+	}
+	return parser
+}
+
+// swapInfo parses and processes the swaps of the fixture
+func (f *txFixture) swapInfo(t *testing.T) *solanaswapgo.SwapInfo {
+	t.Helper()
+	parser := f.parser(t)
+	swapData, err := parser.ParseTransactionForSwap()
+	if err != nil {
+		t.Fatalf("failed to parse swap: %s", err)
+	}
+	swapInfo, err := parser.ProcessSwapData(swapData)
+	if err != nil {
+		t.Fatalf("failed to process swap data: %s", err)
+	}
+	return swapInfo
+}
+
+func newKey() solana.PublicKey {
+	return solana.NewWallet().PublicKey()
+}
+
+// borsh concatenates borsh encoded values: byte slices as is, public keys, u64, u32 and u16 little endian,
+// bools and strings with their length prefix
+func borsh(values ...interface{}) []byte {
+	var data []byte
+	for _, value := range values {
+		switch v := value.(type) {
+		case []byte:
+			data = append(data, v...)
+		case solana.PublicKey:
+			data = append(data, v.Bytes()...)
+		case uint64:
+			data = binary.LittleEndian.AppendUint64(data, v)
+		case int64:
+			data = binary.LittleEndian.AppendUint64(data, uint64(v))
+		case uint32:
+			data = binary.LittleEndian.AppendUint32(data, v)
+		case uint16:
+			data = binary.LittleEndian.AppendUint16(data, v)
+		case uint8:
+			data = append(data, v)
+		case bool:
+			if v {
+				data = append(data, 1)
+			} else {
+				data = append(data, 0)
+			}
+		case string:
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(v)))
+			data = append(data, v...)
+		default:
+			panic(fmt.Sprintf("borsh: unsupported %T", value))
+		}
+	}
+	return data
+}
+
+// transfer adds a TransferChecked of amount from source to destination to the outer instruction at index
+func (f *txFixture) transfer(outer uint16, stackHeight int, source, mint, destination, authority solana.PublicKey, amount uint64, decimals uint8) {
+	f.cpi(outer, stackHeight, solana.TokenProgramID, []solana.PublicKey{source, mint, destination, authority}, borsh(uint8(12), amount, decimals))
+}
@@ -0,0 +1,115 @@
+package quote
+
+import (
+	solanaswapgo "github.com/lonelybeanz/solanaswap-go/solanaswap-go"
+)
+
+// PumpAmmPool is a PumpAmm constant product pool, fees are charged on the quote side
+type PumpAmmPool struct {
+	BaseReserves              uint64
+	QuoteReserves             uint64
+	LpFeeBasisPoints          uint64
+	ProtocolFeeBasisPoints    uint64
+	CoinCreatorFeeBasisPoints uint64
+}
+
+// NewPumpAmmPool quotes a parsed PumpAmm pool, the fee basis points are those of its Buy or Sell event
+func NewPumpAmmPool(pool *solanaswapgo.PumpAmmPool, lpFeeBasisPoints uint64, protocolFeeBasisPoints uint64, coinCreatorFeeBasisPoints uint64) *PumpAmmPool {
+	return &PumpAmmPool{
+		BaseReserves:              pool.PoolBaseTokenReserves,
+		QuoteReserves:             pool.PoolQuoteTokenReserves,
+		LpFeeBasisPoints:          lpFeeBasisPoints,
+		ProtocolFeeBasisPoints:    protocolFeeBasisPoints,
+		CoinCreatorFeeBasisPoints: coinCreatorFeeBasisPoints,
+	}
+}
+
+func (c *PumpAmmPool) feeBasisPoints() uint64 {
+	return c.LpFeeBasisPoints + c.ProtocolFeeBasisPoints + c.CoinCreatorFeeBasisPoints
+}
+
+func (c *PumpAmmPool) fees(quote uint64) uint64 {
+	return fee(quote, c.LpFeeBasisPoints, basisPoints) + fee(quote, c.ProtocolFeeBasisPoints, basisPoints) +
+		fee(quote, c.CoinCreatorFeeBasisPoints, basisPoints)
+}
+
+// BuyExactIn returns the base quoteIn buys, the program takes the base amount out and charges its cost, which
+// can be slightly less than quoteIn
+func (c *PumpAmmPool) BuyExactIn(quoteIn uint64) (*Quote, error) {
+	if quoteIn == 0 {
+		return nil, ErrZeroAmount
+	}
+	spendable, err := mulDiv(quoteIn, basisPoints, basisPoints+c.feeBasisPoints(), false)
+	if err != nil {
+		return nil, err
+	}
+	reserves, err := add(c.QuoteReserves, spendable)
+	if err != nil {
+		return nil, err
+	}
+	baseOut, err := mulDiv(c.BaseReserves, spendable, reserves, false)
+	if err != nil {
+		return nil, err
+	}
+	// the rounded up cost and fees can exceed quoteIn by a few units
+	return largestBuy(baseOut, quoteIn, c.BuyExactOut)
+}
+
+// BuyExactOut returns the quote buying baseOut costs
+func (c *PumpAmmPool) BuyExactOut(baseOut uint64) (*Quote, error) {
+	if baseOut == 0 {
+		return nil, ErrZeroAmount
+	}
+	if baseOut >= c.BaseReserves {
+		return nil, ErrInsufficientLiquidity
+	}
+	quoteIn, err := mulDiv(c.QuoteReserves, baseOut, c.BaseReserves-baseOut, true)
+	if err != nil {
+		return nil, err
+	}
+	fees := c.fees(quoteIn)
+	amountIn, err := add(quoteIn, fees)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{AmountIn: amountIn, AmountOut: baseOut, Fee: fees}, nil
+}
+
+// SellExactIn returns the quote selling baseIn pays
+func (c *PumpAmmPool) SellExactIn(baseIn uint64) (*Quote, error) {
+	if baseIn == 0 {
+		return nil, ErrZeroAmount
+	}
+	reserves, err := add(c.BaseReserves, baseIn)
+	if err != nil {
+		return nil, err
+	}
+	quoteOut, err := mulDiv(c.QuoteReserves, baseIn, reserves, false)
+	if err != nil {
+		return nil, err
+	}
+	fees := c.fees(quoteOut)
+	if fees >= quoteOut {
+		return nil, ErrInsufficientLiquidity
+	}
+	return &Quote{AmountIn: baseIn, AmountOut: quoteOut - fees, Fee: fees}, nil
+}
+
+// SellExactOut returns the base to sell to receive at least quoteOut after fees
+func (c *PumpAmmPool) SellExactOut(quoteOut uint64) (*Quote, error) {
+	if quoteOut == 0 {
+		return nil, ErrZeroAmount
+	}
+	gross, err := grossUp(quoteOut, c.feeBasisPoints(), basisPoints, c.fees)
+	if err != nil {
+		return nil, err
+	}
+	if gross >= c.QuoteReserves {
+		return nil, ErrInsufficientLiquidity
+	}
+	baseIn, err := mulDiv(c.BaseReserves, gross, c.QuoteReserves-gross, true)
+	if err != nil {
+		return nil, err
+	}
+	return c.SellExactIn(baseIn)
+}
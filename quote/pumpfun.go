@@ -0,0 +1,117 @@
+package quote
+
+import (
+	solanaswapgo "github.com/lonelybeanz/solanaswap-go/solanaswap-go"
+)
+
+const basisPoints = 10_000
+
+// PumpfunCurve is a pump.fun bonding curve, quote is SOL in lamports
+type PumpfunCurve struct {
+	VirtualSolReserves    uint64
+	VirtualTokenReserves  uint64
+	RealTokenReserves     uint64
+	FeeBasisPoints        uint64 // protocol fee
+	CreatorFeeBasisPoints uint64
+}
+
+// NewPumpfunCurve quotes a parsed pump.fun pool, the fee basis points are those of its TradeEvent
+func NewPumpfunCurve(pool *solanaswapgo.PumpFunPool, feeBasisPoints uint64, creatorFeeBasisPoints uint64) *PumpfunCurve {
+	return &PumpfunCurve{
+		VirtualSolReserves:    pool.VirtualSolReserves,
+		VirtualTokenReserves:  pool.VirtualTokenReserves,
+		RealTokenReserves:     pool.RealTokenReserves,
+		FeeBasisPoints:        feeBasisPoints,
+		CreatorFeeBasisPoints: creatorFeeBasisPoints,
+	}
+}
+
+func (c *PumpfunCurve) fees(sol uint64) uint64 {
+	return fee(sol, c.FeeBasisPoints, basisPoints) + fee(sol, c.CreatorFeeBasisPoints, basisPoints)
+}
+
+// BuyExactIn returns the tokens quoteIn lamports buy, the program charges the cost of those tokens which can be
+// slightly less than quoteIn
+func (c *PumpfunCurve) BuyExactIn(quoteIn uint64) (*Quote, error) {
+	if quoteIn == 0 {
+		return nil, ErrZeroAmount
+	}
+	spendable, err := mulDiv(quoteIn, basisPoints, basisPoints+c.FeeBasisPoints+c.CreatorFeeBasisPoints, false)
+	if err != nil {
+		return nil, err
+	}
+	reserves, err := add(c.VirtualSolReserves, spendable)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := mulDiv(spendable, c.VirtualTokenReserves, reserves, false)
+	if err != nil {
+		return nil, err
+	}
+	// the rounded up cost and fees can exceed quoteIn by a few units
+	return largestBuy(tokens, quoteIn, c.BuyExactOut)
+}
+
+// BuyExactOut returns the lamports buying baseOut tokens costs, capped to the tokens left on the curve
+func (c *PumpfunCurve) BuyExactOut(baseOut uint64) (*Quote, error) {
+	if baseOut > c.RealTokenReserves {
+		baseOut = c.RealTokenReserves
+	}
+	if baseOut == 0 {
+		return nil, ErrZeroAmount
+	}
+	if baseOut >= c.VirtualTokenReserves {
+		return nil, ErrInsufficientLiquidity
+	}
+	// the program rounds the SOL reserves after the buy down and adds one lamport
+	solAfter, err := mulDiv(c.VirtualSolReserves, c.VirtualTokenReserves, c.VirtualTokenReserves-baseOut, false)
+	if err != nil {
+		return nil, err
+	}
+	cost := solAfter + 1 - c.VirtualSolReserves
+	fees := c.fees(cost)
+	amountIn, err := add(cost, fees)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{AmountIn: amountIn, AmountOut: baseOut, Fee: fees}, nil
+}
+
+// SellExactIn returns the lamports selling baseIn tokens pays
+func (c *PumpfunCurve) SellExactIn(baseIn uint64) (*Quote, error) {
+	if baseIn == 0 {
+		return nil, ErrZeroAmount
+	}
+	reserves, err := add(c.VirtualTokenReserves, baseIn)
+	if err != nil {
+		return nil, err
+	}
+	sol, err := mulDiv(baseIn, c.VirtualSolReserves, reserves, false)
+	if err != nil {
+		return nil, err
+	}
+	fees := c.fees(sol)
+	if fees >= sol {
+		return nil, ErrInsufficientLiquidity
+	}
+	return &Quote{AmountIn: baseIn, AmountOut: sol - fees, Fee: fees}, nil
+}
+
+// SellExactOut returns the tokens to sell to receive at least quoteOut lamports after fees
+func (c *PumpfunCurve) SellExactOut(quoteOut uint64) (*Quote, error) {
+	if quoteOut == 0 {
+		return nil, ErrZeroAmount
+	}
+	gross, err := grossUp(quoteOut, c.FeeBasisPoints+c.CreatorFeeBasisPoints, basisPoints, c.fees)
+	if err != nil {
+		return nil, err
+	}
+	if gross >= c.VirtualSolReserves {
+		return nil, ErrInsufficientLiquidity
+	}
+	tokens, err := mulDiv(gross, c.VirtualTokenReserves, c.VirtualSolReserves-gross, true)
+	if err != nil {
+		return nil, err
+	}
+	return c.SellExactIn(tokens)
+}
@@ -0,0 +1,115 @@
+// Package quote simulates trades on the pump.fun, PumpAmm and Raydium Launchpad curves from the reserve snapshots
+// of the parser, with the fees and the rounding of the programs, for slippage checks and to verify parsed amounts.
+package quote
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrZeroAmount            = errors.New("amount is zero")
+	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+	ErrOverflow              = errors.New("amount overflows u64")
+)
+
+// Quote is a simulated trade, in base units of the tokens traded
+type Quote struct {
+	AmountIn  uint64 // paid by the trader, fees included
+	AmountOut uint64 // received by the trader, fees deducted
+	Fee       uint64 // fees of the trade, in quote units
+}
+
+// Curve quotes the four trade directions of a pool, base is the token and quote the currency it trades against
+type Curve interface {
+	// BuyExactIn spends quoteIn, fees included, on base
+	BuyExactIn(quoteIn uint64) (*Quote, error)
+	// BuyExactOut buys baseOut and returns the quote it costs
+	BuyExactOut(baseOut uint64) (*Quote, error)
+	// SellExactIn sells baseIn for quote
+	SellExactIn(baseIn uint64) (*Quote, error)
+	// SellExactOut sells the base needed to receive quoteOut after fees
+	SellExactOut(quoteOut uint64) (*Quote, error)
+}
+
+// mulDiv returns a * b / c rounded down, or up when ceil is set, computed on 128 bits like the programs do
+func mulDiv(a uint64, b uint64, c uint64, ceil bool) (uint64, error) {
+	if c == 0 {
+		return 0, ErrInsufficientLiquidity
+	}
+	product := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	divisor := new(big.Int).SetUint64(c)
+	if ceil {
+		product.Add(product, new(big.Int).Sub(divisor, big.NewInt(1)))
+	}
+	result := product.Div(product, divisor)
+	if !result.IsUint64() {
+		return 0, ErrOverflow
+	}
+	return result.Uint64(), nil
+}
+
+// fee returns amount * rate / denominator rounded up, the programs never round a fee down
+func fee(amount uint64, rate uint64, denominator uint64) uint64 {
+	value, err := mulDiv(amount, rate, denominator, true)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// add returns a + b, failing on overflow
+func add(a uint64, b uint64) (uint64, error) {
+	if a+b < a {
+		return 0, ErrOverflow
+	}
+	return a + b, nil
+}
+
+// largestBuy returns the quote of the largest output up to maxOut that costs at most quoteIn, the cost of
+// buyExactOut growing with its output
+func largestBuy(maxOut uint64, quoteIn uint64, buyExactOut func(uint64) (*Quote, error)) (*Quote, error) {
+	if maxOut == 0 {
+		return nil, ErrZeroAmount
+	}
+	best, err := buyExactOut(maxOut)
+	if err != nil || best.AmountIn <= quoteIn {
+		return best, err
+	}
+	best = nil
+	low, high := uint64(1), maxOut-1
+	for low <= high {
+		out := low + (high-low)/2
+		q, err := buyExactOut(out)
+		if err != nil {
+			return nil, err
+		}
+		if q.AmountIn <= quoteIn {
+			best, low = q, out+1
+		} else {
+			high = out - 1
+		}
+	}
+	if best == nil {
+		return nil, ErrZeroAmount
+	}
+	return best, nil
+}
+
+// grossUp returns the amount that is worth at least net once fees are deducted, rate is the sum of the fee
+// rates, fees applies them with their separate roundings
+func grossUp(net uint64, rate uint64, denominator uint64, fees func(uint64) uint64) (uint64, error) {
+	if rate >= denominator {
+		return 0, ErrInsufficientLiquidity
+	}
+	gross, err := mulDiv(net, denominator, denominator-rate, true)
+	if err != nil {
+		return 0, err
+	}
+	for fees(gross) > gross || gross-fees(gross) < net {
+		if gross, err = add(gross, 1); err != nil {
+			return 0, err
+		}
+	}
+	return gross, nil
+}
@@ -0,0 +1,140 @@
+package quote
+
+import (
+	solanaswapgo "github.com/lonelybeanz/solanaswap-go/solanaswap-go"
+)
+
+// feeRateDenominator is the denominator of the Raydium Launchpad fee rates
+const feeRateDenominator = 1_000_000
+
+// RaydiumLaunchpadCurve is a Raydium Launchpad constant product curve, trading on the virtual reserves plus the
+// quote raised and minus the base sold. FeeRate is the sum of the trade, platform and share fee rates, in
+// millionths of the quote traded.
+type RaydiumLaunchpadCurve struct {
+	VirtualBase  uint64
+	VirtualQuote uint64
+	RealBase     uint64
+	RealQuote    uint64
+	FeeRate      uint64
+}
+
+// NewRaydiumLaunchpadCurve quotes a parsed Raydium Launchpad pool, the parser leaves the real reserves after its trade
+func NewRaydiumLaunchpadCurve(pool *solanaswapgo.RaydiumLaunchpadPool, feeRate uint64) *RaydiumLaunchpadCurve {
+	return &RaydiumLaunchpadCurve{
+		VirtualBase:  pool.VirtualBase,
+		VirtualQuote: pool.VirtualQuote,
+		RealBase:     pool.RealBaseBefore,
+		RealQuote:    pool.RealQuoteBefore,
+		FeeRate:      feeRate,
+	}
+}
+
+// reserves returns the base and quote reserves the curve trades on
+func (c *RaydiumLaunchpadCurve) reserves() (base uint64, quote uint64, err error) {
+	if c.RealBase >= c.VirtualBase {
+		return 0, 0, ErrInsufficientLiquidity
+	}
+	quote, err = add(c.VirtualQuote, c.RealQuote)
+	return c.VirtualBase - c.RealBase, quote, err
+}
+
+func (c *RaydiumLaunchpadCurve) fees(quote uint64) uint64 {
+	return fee(quote, c.FeeRate, feeRateDenominator)
+}
+
+// BuyExactIn returns the base quoteIn buys
+func (c *RaydiumLaunchpadCurve) BuyExactIn(quoteIn uint64) (*Quote, error) {
+	if quoteIn == 0 {
+		return nil, ErrZeroAmount
+	}
+	baseReserves, quoteReserves, err := c.reserves()
+	if err != nil {
+		return nil, err
+	}
+	fees := c.fees(quoteIn)
+	if fees >= quoteIn {
+		return nil, ErrInsufficientLiquidity
+	}
+	reserves, err := add(quoteReserves, quoteIn-fees)
+	if err != nil {
+		return nil, err
+	}
+	baseOut, err := mulDiv(quoteIn-fees, baseReserves, reserves, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{AmountIn: quoteIn, AmountOut: baseOut, Fee: fees}, nil
+}
+
+// BuyExactOut returns the quote buying baseOut costs
+func (c *RaydiumLaunchpadCurve) BuyExactOut(baseOut uint64) (*Quote, error) {
+	if baseOut == 0 {
+		return nil, ErrZeroAmount
+	}
+	baseReserves, quoteReserves, err := c.reserves()
+	if err != nil {
+		return nil, err
+	}
+	if baseOut >= baseReserves {
+		return nil, ErrInsufficientLiquidity
+	}
+	if c.FeeRate >= feeRateDenominator {
+		return nil, ErrInsufficientLiquidity
+	}
+	quoteIn, err := mulDiv(baseOut, quoteReserves, baseReserves-baseOut, true)
+	if err != nil {
+		return nil, err
+	}
+	amountIn, err := mulDiv(quoteIn, feeRateDenominator, feeRateDenominator-c.FeeRate, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{AmountIn: amountIn, AmountOut: baseOut, Fee: amountIn - quoteIn}, nil
+}
+
+// SellExactIn returns the quote selling baseIn pays
+func (c *RaydiumLaunchpadCurve) SellExactIn(baseIn uint64) (*Quote, error) {
+	if baseIn == 0 {
+		return nil, ErrZeroAmount
+	}
+	baseReserves, quoteReserves, err := c.reserves()
+	if err != nil {
+		return nil, err
+	}
+	reserves, err := add(baseReserves, baseIn)
+	if err != nil {
+		return nil, err
+	}
+	quoteOut, err := mulDiv(baseIn, quoteReserves, reserves, false)
+	if err != nil {
+		return nil, err
+	}
+	fees := c.fees(quoteOut)
+	if fees >= quoteOut {
+		return nil, ErrInsufficientLiquidity
+	}
+	return &Quote{AmountIn: baseIn, AmountOut: quoteOut - fees, Fee: fees}, nil
+}
+
+// SellExactOut returns the base to sell to receive quoteOut after fees
+func (c *RaydiumLaunchpadCurve) SellExactOut(quoteOut uint64) (*Quote, error) {
+	if quoteOut == 0 {
+		return nil, ErrZeroAmount
+	}
+	baseReserves, quoteReserves, err := c.reserves()
+	if err != nil {
+		return nil, err
+	}
+	gross, err := grossUp(quoteOut, c.FeeRate, feeRateDenominator, c.fees)
+	if err != nil {
+		return nil, err
+	}
+	if gross >= quoteReserves {
+		return nil, ErrInsufficientLiquidity
+	}
+	baseIn, err := mulDiv(gross, baseReserves, quoteReserves-gross, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{AmountIn: baseIn, AmountOut: quoteOut, Fee: gross - quoteOut}, nil
+}
@@ -0,0 +1,217 @@
+package solanaswapgo
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+// invocation is an instruction of the call tree of an outer instruction, its children are the CPIs it made
+type invocation struct {
+	ProgramID   solana.PublicKey
+	Instruction solana.CompiledInstruction
+	InnerIndex  int // -1 for the outer instruction
+	StackHeight int
+	Children    []*invocation
+}
+
+// invocationTree rebuilds the CPIs of an outer instruction from the stack heights of its inner instructions,
+// or the invoke depths of the logs, ok is false when neither was recorded
+func (p *Parser) invocationTree(instructionIndex int) (root *invocation, ok bool) {
+	outerInstruction := p.txInfo.Message.Instructions[instructionIndex]
+	root = &invocation{
+		ProgramID:   p.allAccountKeys[outerInstruction.ProgramIDIndex],
+		Instruction: outerInstruction,
+		InnerIndex:  -1,
+		StackHeight: 1,
+	}
+
+	ok = true
+	var logs map[[2]int]*ProgramInvocation
+	stack := []*invocation{root}
+	for j, inner := range p.getInnerInstructions(instructionIndex) {
+		height := int(inner.StackHeight)
+		if height < 2 {
+			// the invoke depth printed in the logs is the stack height
+			if logs == nil {
+				logs = p.matchInvocationLogs()
+			}
+			if log := logs[[2]int{instructionIndex, j}]; log != nil && log.Depth >= 2 {
+				height = log.Depth
+			} else {
+				ok = false
+				height = 2
+			}
+		}
+		node := &invocation{
+			ProgramID:   p.allAccountKeys[inner.ProgramIDIndex],
+			Instruction: p.convertRPCToSolanaInstruction(inner),
+			InnerIndex:  j,
+			StackHeight: height,
+		}
+		for len(stack) > 1 && stack[len(stack)-1].StackHeight >= height {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, node)
+	}
+	return root, ok
+}
+
+// cpiEnds returns for every inner instruction of an outer instruction the index after the last instruction of
+// its call subtree, innerInstructions[j+1:ends[j]] are the CPIs the instruction at j made. Without stack heights
+// these cannot be told apart from the instructions of its caller and the subtree runs to the end.
+func (p *Parser) cpiEnds(instructionIndex int) []int {
+	innerInstructions := p.getInnerInstructions(instructionIndex)
+	ends := make([]int, len(innerInstructions))
+	root, ok := p.invocationTree(instructionIndex)
+	if !ok {
+		for j := range ends {
+			ends[j] = len(innerInstructions)
+		}
+		return ends
+	}
+
+	root.walk(func(node *invocation) {
+		if node.InnerIndex < 0 {
+			return
+		}
+		last := node
+		for len(last.Children) > 0 {
+			last = last.Children[len(last.Children)-1]
+		}
+		ends[node.InnerIndex] = last.InnerIndex + 1
+	})
+	return ends
+}
+
+// matchInvocationLogs pairs the invocations of the logs with the instructions executing them, keyed by outer
+// and inner index. Precompiles print no logs and are left without one.
+func (p *Parser) matchInvocationLogs() map[[2]int]*ProgramInvocation {
+	logs := p.invocationLogs()
+	matched := make(map[[2]int]*ProgramInvocation)
+	cursor := 0
+	match := func(key [2]int, programIDIndex uint16) {
+		if cursor < len(logs) && logs[cursor].ProgramID.Equals(p.allAccountKeys[programIDIndex]) {
+			matched[key] = logs[cursor]
+			cursor++
+		}
+	}
+	for i, outerInstruction := range p.txInfo.Message.Instructions {
+		match([2]int{i, -1}, outerInstruction.ProgramIDIndex)
+		for j, inner := range p.getInnerInstructions(i) {
+			match([2]int{i, j}, inner.ProgramIDIndex)
+		}
+	}
+	return matched
+}
+
+// InstructionNode is an instruction of a transaction with the CPIs it made
+type InstructionNode struct {
+	ProgramID    solana.PublicKey
+	Accounts     []solana.PublicKey
+	Data         []byte
+	Name         string // instruction name decoded from the discriminator, empty when unknown
+	OuterIndex   int
+	InnerIndex   int // -1 for outer instructions
+	StackHeight  int
+	Logs         []string // log lines printed by this invocation, without those of its CPIs
+	ComputeUnits uint64
+	Children     []*InstructionNode
+}
+
+// InstructionTree returns the outer instructions of the transaction with their nested CPIs
+func (p *Parser) InstructionTree() []*InstructionNode {
+	logs := p.matchInvocationLogs()
+
+	var convert func(outerIndex int, node *invocation) *InstructionNode
+	convert = func(outerIndex int, node *invocation) *InstructionNode {
+		instructionNode := &InstructionNode{
+			ProgramID:   node.ProgramID,
+			Accounts:    make([]solana.PublicKey, 0, len(node.Instruction.Accounts)),
+			Data:        []byte(node.Instruction.Data),
+			Name:        instructionName(node.ProgramID, node.Instruction.Data),
+			OuterIndex:  outerIndex,
+			InnerIndex:  node.InnerIndex,
+			StackHeight: node.StackHeight,
+		}
+		for _, index := range node.Instruction.Accounts {
+			if int(index) < len(p.allAccountKeys) {
+				instructionNode.Accounts = append(instructionNode.Accounts, p.allAccountKeys[index])
+			}
+		}
+		if log := logs[[2]int{outerIndex, node.InnerIndex}]; log != nil {
+			instructionNode.Logs = log.Logs
+			instructionNode.ComputeUnits = log.ComputeUnits
+		}
+		for _, child := range node.Children {
+			instructionNode.Children = append(instructionNode.Children, convert(outerIndex, child))
+		}
+		return instructionNode
+	}
+
+	var tree []*InstructionNode
+	for i := range p.txInfo.Message.Instructions {
+		root, _ := p.invocationTree(i)
+		tree = append(tree, convert(i, root))
+	}
+	return tree
+}
+
+// Walk visits the node and its CPIs in execution order
+func (n *InstructionNode) Walk(visit func(*InstructionNode)) {
+	visit(n)
+	for _, child := range n.Children {
+		child.Walk(visit)
+	}
+}
+
+// walk visits the invocation and its descendants in execution order
+func (n *invocation) walk(visit func(*invocation)) {
+	visit(n)
+	for _, child := range n.Children {
+		child.walk(visit)
+	}
+}
+
+// processVenueTransfers returns the transfers issued directly by the invocations of programIDs under an
+// outer instruction, so that fees and rent paid by the caller are not taken for the venue's swap. Without
+// stack heights the transfers cannot be told apart and fallback reads the whole instruction.
+func (p *Parser) processVenueTransfers(instructionIndex int, swapType SwapType, fallback func(int) []SwapData, programIDs ...solana.PublicKey) []SwapData {
+	root, ok := p.invocationTree(instructionIndex)
+	if !ok {
+		return fallback(instructionIndex)
+	}
+
+	var swaps []SwapData
+	root.walk(func(node *invocation) {
+		if !containsPublicKey(programIDs, node.ProgramID) {
+			return
+		}
+		for _, child := range node.Children {
+			switch {
+			case p.isTransferCheck(child.Instruction):
+				if transfer := p.processTransferCheck(child.Instruction); transfer != nil {
+					swaps = append(swaps, SwapData{Type: swapType, Data: transfer})
+				}
+			case p.isTokenTransfer(child.Instruction):
+				if transfer := p.processTokenTransfer(child.Instruction); transfer != nil {
+					swaps = append(swaps, SwapData{Type: swapType, Data: transfer})
+				}
+			case p.isSystemTransfer(child.Instruction):
+				if transfer := p.processSystemTransfer(child.Instruction); transfer != nil {
+					swaps = append(swaps, SwapData{Type: swapType, Data: transfer})
+				}
+			}
+		}
+	})
+	return swaps
+}
+
+func containsPublicKey(keys []solana.PublicKey, key solana.PublicKey) bool {
+	for _, k := range keys {
+		if k.Equals(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/gagliardetto/solana-go"
+	"gopkg.in/yaml.v3"
+)
+
+type ProgramCategory string
+
+const (
+	CATEGORY_ROUTER    ProgramCategory = "router"
+	CATEGORY_BOT       ProgramCategory = "bot"
+	CATEGORY_AMM       ProgramCategory = "amm"
+	CATEGORY_LAUNCHPAD ProgramCategory = "launchpad"
+)
+
+// ParseStrategy tells the parser how to read the swaps of a catalog program, entries without
+// a strategy are only used to attribute swaps made through another program
+type ParseStrategy string
+
+const (
+	STRATEGY_INNER_INSTRUCTION_ROUTER ParseStrategy = "inner-instruction-router" // swaps are CPIs into known venues
+	STRATEGY_TRANSFER_BASED           ParseStrategy = "transfer-based"           // swaps are read from the token and SOL transfers
+)
+
+// CatalogEntry maps the programs and fee wallets of a router, bot, AMM or launchpad to its name
+type CatalogEntry struct {
+	Name       string             `json:"name" yaml:"name"`
+	Category   ProgramCategory    `json:"category" yaml:"category"`
+	Strategy   ParseStrategy      `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	SwapType   SwapType           `json:"swap_type,omitempty" yaml:"swap_type,omitempty"` // AMM reported for transfer-based swaps, Name when empty
+	ProgramIDs []solana.PublicKey `json:"program_ids" yaml:"program_ids"`
+	FeeWallets []solana.PublicKey `json:"fee_wallets,omitempty" yaml:"fee_wallets,omitempty"`
+}
+
+type ProgramCatalog struct {
+	Programs []CatalogEntry `json:"programs" yaml:"programs"`
+
+	byProgram   map[solana.PublicKey]*CatalogEntry
+	byFeeWallet map[solana.PublicKey]*CatalogEntry
+}
+
+//go:embed catalog.json
+var embeddedCatalog []byte
+
+var programCatalog atomic.Pointer[ProgramCatalog]
+
+func init() {
+	catalog, err := ParseProgramCatalog(embeddedCatalog)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded program catalog: %s", err))
+	}
+	programCatalog.Store(catalog)
+}
+
+// ParseProgramCatalog decodes a JSON or YAML program catalog, JSON when the document is an object
+func ParseProgramCatalog(data []byte) (*ProgramCatalog, error) {
+	catalog := &ProgramCatalog{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(data, catalog); err != nil {
+			return nil, err
+		}
+	} else if err := yaml.Unmarshal(data, catalog); err != nil {
+		return nil, err
+	}
+
+	catalog.byProgram = make(map[solana.PublicKey]*CatalogEntry)
+	catalog.byFeeWallet = make(map[solana.PublicKey]*CatalogEntry)
+	for i := range catalog.Programs {
+		entry := &catalog.Programs[i]
+		if entry.Name == "" {
+			return nil, fmt.Errorf("program catalog entry %d has no name", i)
+		}
+		switch entry.Strategy {
+		case "", STRATEGY_INNER_INSTRUCTION_ROUTER, STRATEGY_TRANSFER_BASED:
+		default:
+			return nil, fmt.Errorf("program catalog entry %s has unknown strategy %q", entry.Name, entry.Strategy)
+		}
+		for _, programID := range entry.ProgramIDs {
+			catalog.byProgram[programID] = entry
+		}
+		for _, wallet := range entry.FeeWallets {
+			catalog.byFeeWallet[wallet] = entry
+		}
+	}
+	return catalog, nil
+}
+
+// LoadProgramCatalog replaces the embedded program catalog with the JSON or YAML file at path
+func LoadProgramCatalog(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read program catalog: %w", err)
+	}
+	catalog, err := ParseProgramCatalog(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse program catalog %s: %w", path, err)
+	}
+	programCatalog.Store(catalog)
+	return nil
+}
+
+// CurrentProgramCatalog returns the program catalog in use
+func CurrentProgramCatalog() *ProgramCatalog {
+	return programCatalog.Load()
+}
+
+// Program returns the catalog entry of a program, nil when it is not in the catalog
+func (c *ProgramCatalog) Program(programID solana.PublicKey) *CatalogEntry {
+	return c.byProgram[programID]
+}
+
+// FeeWallet returns the catalog entry collecting its fee at wallet, nil when it is not in the catalog
+func (c *ProgramCatalog) FeeWallet(wallet solana.PublicKey) *CatalogEntry {
+	return c.byFeeWallet[wallet]
+}
+
+// swapType is the SwapType reported for the swaps read from the entry's transfers
+func (e *CatalogEntry) swapType() SwapType {
+	if e.SwapType != "" {
+		return e.SwapType
+	}
+	return SwapType(e.Name)
+}
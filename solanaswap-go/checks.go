@@ -109,6 +109,17 @@ func (p *Parser) isPumpfunCreateEventInstruction(inst rpc.CompiledInstruction) b
 	return bytes.Equal(decodedBytes[:16], PumpfunCreateEventDiscriminator[:])
 }
 
+func (p *Parser) isPumpfunCompleteEventInstruction(inst rpc.CompiledInstruction) bool {
+	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(PUMP_FUN_PROGRAM_ID) || len(inst.Data) < 16 {
+		return false
+	}
+	decodedBytes, err := base58.Decode(inst.Data.String())
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(decodedBytes[:16], PumpfunCompleteEventDiscriminator[:])
+}
+
 func (p *Parser) isPumpFunTradeEventInstruction(inst rpc.CompiledInstruction) bool {
 	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(PUMP_FUN_PROGRAM_ID) || len(inst.Data) < 16 {
 		return false
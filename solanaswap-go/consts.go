@@ -4,10 +4,12 @@ import "github.com/gagliardetto/solana-go"
 
 var (
 	JUPITER_PROGRAM_ID                        = solana.MustPublicKeyFromBase58("JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4")
+	JUPITER_LIMIT_ORDER_PROGRAM_ID            = solana.MustPublicKeyFromBase58("jupoNjAxXgZ4rjzxzPMP4oxduvQsQtZzyknqvzYNrNu")
+	JUPITER_LIMIT_ORDER_V2_PROGRAM_ID         = solana.MustPublicKeyFromBase58("j1o2qRpjcyUwEvwtcfhEQefh773ZgjxcVRry7LDqg5X")
 	JUPITER_DCA_PROGRAM_ID                    = solana.MustPublicKeyFromBase58("DCAK36VfExkPdAkYUQg6ewgxyinvcEyPLyHjRbmveKFw")
 	PUMP_FUN_PROGRAM_ID                       = solana.MustPublicKeyFromBase58("6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P")
 	PUMP_AMM_PROGRAM_ID                       = solana.MustPublicKeyFromBase58("pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA") //newly added
-	PHOENIX_PROGRAM_ID                        = solana.MustPublicKeyFromBase58("PhoeNiXZ8ByJGLkxNfZRnkUfjvmuYqLR89jjFHGqdXY") // not supported yet
+	PHOENIX_PROGRAM_ID                        = solana.MustPublicKeyFromBase58("PhoeNiXZ8ByJGLkxNfZRnkUfjvmuYqLR89jjFHGqdXY")
 	BANANA_GUN_PROGRAM_ID                     = solana.MustPublicKeyFromBase58("BANANAjs7FJiPQqJTGFzkZJndT9o7UmKiYYGaJz6frGu")
 	MINTECH_PROGRAM_ID                        = solana.MustPublicKeyFromBase58("minTcHYRLVPubRK8nt6sqe2ZpWrGDLQoNLipDJCGocY")
 	BLOOM_PROGRAM_ID                          = solana.MustPublicKeyFromBase58("b1oomGGqPKGD6errbyfbVMBuzSC8WtAAYo8MwNafWW1")
@@ -25,31 +27,107 @@ var (
 		solana.MustPublicKeyFromBase58("Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB"),
 		solana.MustPublicKeyFromBase58("dbcij3LWUppWqq96dh6gJWwBifmcGfLSB5D4DuSMaqN"),
 	}
-	METEORA_PROGRAM_ID         = solana.MustPublicKeyFromBase58("LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo")
-	METEORA_DBC_PROGRAM_ID     = solana.MustPublicKeyFromBase58("dbcij3LWUppWqq96dh6gJWwBifmcGfLSB5D4DuSMaqN") //Meteora Dynamic Bonding Curve Program
-	METEORA_POOLS_PROGRAM_ID   = solana.MustPublicKeyFromBase58("Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB")
-	MOONSHOT_PROGRAM_ID        = solana.MustPublicKeyFromBase58("MoonCVVNZFSYkqNXP6bxHLPL6QQJiMagDL3qcqUQTrG")
-	ORCA_PROGRAM_ID            = solana.MustPublicKeyFromBase58("whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc")
-	OKX_DEX_ROUTER_PROGRAM_ID  = solana.MustPublicKeyFromBase58("6m2CDdhRgxpH4WjvdzxAYbGxwdGUz5MziiL5jek2kBma")
-	PHOTON_PROGRAM_ID          = solana.MustPublicKeyFromBase58("BSfD6SHZigAfDWSjzD5Q41jw8LmKwtmjskPH9XW1mrRW")
-	AXIOM_PROGRAM_ID2          = solana.MustPublicKeyFromBase58("AxiomQpD1TrYEHNYLts8h3ko1NHdtxfgNgHryj2hJJx4")
-	AXIOM_PROGRAM_ID           = solana.MustPublicKeyFromBase58("Axiom3a2w1UbMt2SMgqSvRiuJFTPusDhwKamNgPTeNQ9")
-	NATIVE_SOL_MINT_PROGRAM_ID = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	METEORA_PROGRAM_ID                  = solana.MustPublicKeyFromBase58("LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo")
+	METEORA_DBC_PROGRAM_ID              = solana.MustPublicKeyFromBase58("dbcij3LWUppWqq96dh6gJWwBifmcGfLSB5D4DuSMaqN") //Meteora Dynamic Bonding Curve Program
+	METEORA_POOLS_PROGRAM_ID            = solana.MustPublicKeyFromBase58("Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB")
+	MOONSHOT_PROGRAM_ID                 = solana.MustPublicKeyFromBase58("MoonCVVNZFSYkqNXP6bxHLPL6QQJiMagDL3qcqUQTrG")
+	SOLFI_PROGRAM_ID                    = solana.MustPublicKeyFromBase58("SoLFiHG9TfgtdUXUjWAxi3LtvYuFyDLVhBWxdMZxyCe")
+	OBRIC_V2_PROGRAM_ID                 = solana.MustPublicKeyFromBase58("obriQD1zbpyLz95G5n7nJe6a4DPjpFwa5XYPoNm113y")
+	ZEROFI_PROGRAM_ID                   = solana.MustPublicKeyFromBase58("ZERor4xhbUycZ6gb9ntrhqscUcZmAbQDjEAtCf4hbZY")
+	HUMIDIFI_PROGRAM_ID                 = solana.MustPublicKeyFromBase58("9H6tua7jkLhdm3w8BvgpTn5LZNU7g4ZynDmCiNN3q6Rp")
+	LIFINITY_V2_PROGRAM_ID              = solana.MustPublicKeyFromBase58("2wT8Yq49kHgDzXuPxZSaeLaH1qbmGXtEyPy64bL7aD3c")
+	OPENBOOK_V2_PROGRAM_ID              = solana.MustPublicKeyFromBase58("opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb")
+	SABER_STABLE_SWAP_PROGRAM_ID        = solana.MustPublicKeyFromBase58("SSwpkEEcbUqx4vtoEByFjSkhKdCT862DNVb52nZg1UZ")
+	MERCURIAL_PROGRAM_ID                = solana.MustPublicKeyFromBase58("MERLuDFBMmsHnsBPZw2sDQZHvXFMwp8EdjudcU2HKky")
+	STABBLE_STABLE_SWAP_PROGRAM_ID      = solana.MustPublicKeyFromBase58("swapNyd8XiQwJ6ianp9snpu4brUqFxadzvHebnAXjJZ")
+	STABBLE_WEIGHTED_SWAP_PROGRAM_ID    = solana.MustPublicKeyFromBase58("swapFpHZwjELNnjvThjajtiVmkz3yPQEHjLtka2fwHW")
+	SANCTUM_INFINITY_PROGRAM_ID         = solana.MustPublicKeyFromBase58("5ocnV1qiCgaQR8Jb8xWnVbApfaygJ8tNoZfgPwsgx9kx")
+	SANCTUM_ROUTER_PROGRAM_ID           = solana.MustPublicKeyFromBase58("stkitrT1Uoy18Dk1fTrgPw8W1tvaYu4L2b5EjdJ1xkx")
+	SPL_STAKE_POOL_PROGRAM_ID           = solana.MustPublicKeyFromBase58("SPoo1Ku8WFXoNDMHPsrGSTSG1Y47rzgn41SLUNakuHy")
+	SANCTUM_SPL_STAKE_POOL_PROGRAM_ID   = solana.MustPublicKeyFromBase58("SP12tWFxD9oJsVWNavTTBZvMbA6gkAmxtVgxdqvyvhY")
+	SANCTUM_MULTI_STAKE_POOL_PROGRAM_ID = solana.MustPublicKeyFromBase58("SPMBzsVUuoHA4Jm6KunbsotaahvVikZs1JyTW6iJvbn")
+	METEORA_VAULT_PROGRAM_ID            = solana.MustPublicKeyFromBase58("24Uqj9JCLxUeoC3hGfh5W3s9FM9uCHDS2SG3LYwBpyTi")
+	METEORA_DAMM_V2_PROGRAM_ID          = solana.MustPublicKeyFromBase58("cpamdpZCGKUy5JxQXB4dcpGPiikHawvSWAd6mEn1sGG")
+	ORCA_PROGRAM_ID                     = solana.MustPublicKeyFromBase58("whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc")
+	OKX_DEX_ROUTER_PROGRAM_ID           = solana.MustPublicKeyFromBase58("6m2CDdhRgxpH4WjvdzxAYbGxwdGUz5MziiL5jek2kBma")
+	OKX_DEX_ROUTER_V1_PROGRAM_ID        = solana.MustPublicKeyFromBase58("HV1KXxWFaSeriyFvXyx48FqG9BoFbfinB8njCJonqP7K")
+	OKX_DEX_PROXY_PROGRAM_ID            = solana.MustPublicKeyFromBase58("proVF4pMXVaYqmy4NjniPh4pqKNfMmsihgd4wdkCX3u")
+	PHOTON_PROGRAM_ID                   = solana.MustPublicKeyFromBase58("BSfD6SHZigAfDWSjzD5Q41jw8LmKwtmjskPH9XW1mrRW")
+	AXIOM_PROGRAM_ID2                   = solana.MustPublicKeyFromBase58("AxiomQpD1TrYEHNYLts8h3ko1NHdtxfgNgHryj2hJJx4")
+	AXIOM_PROGRAM_ID                    = solana.MustPublicKeyFromBase58("Axiom3a2w1UbMt2SMgqSvRiuJFTPusDhwKamNgPTeNQ9")
+	NATIVE_SOL_MINT_PROGRAM_ID          = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
 )
 
 type SwapType string
 
 const (
-	PUMP_FUN          SwapType = "PumpFun"
-	PUMP_SWAP         SwapType = "PumpAmm" //newly added
-	JUPITER           SwapType = "Jupiter"
-	RAYDIUM           SwapType = "Raydium"
-	RAYDIUM_Launchpad SwapType = "RaydiumLaunchpad"
-	OKX               SwapType = "OKX"
-	ORCA              SwapType = "Orca"
-	METEORA           SwapType = "Meteora"
-	METEORA_DBC       SwapType = "MeteoraDbc"
-	AXION             SwapType = "Axion"
-	MOONSHOT          SwapType = "Moonshot"
-	UNKNOWN           SwapType = "Unknown"
+	PUMP_FUN            SwapType = "PumpFun"
+	PUMP_SWAP           SwapType = "PumpAmm" //newly added
+	JUPITER             SwapType = "Jupiter"
+	JUPITER_DCA         SwapType = "JupiterDCA"
+	JUPITER_LIMIT_ORDER SwapType = "JupiterLimitOrder"
+	RAYDIUM             SwapType = "Raydium"
+	RAYDIUM_Launchpad   SwapType = "RaydiumLaunchpad"
+	OKX                 SwapType = "OKX"
+	ORCA                SwapType = "Orca"
+	METEORA             SwapType = "Meteora"
+	METEORA_DBC         SwapType = "MeteoraDbc"
+	METEORA_DAMM        SwapType = "MeteoraDamm"
+	METEORA_DAMM_V2     SwapType = "MeteoraDammV2"
+	RAYDIUM_CPMM        SwapType = "RaydiumCPMM"
+	AXION               SwapType = "Axion"
+	MOONSHOT            SwapType = "Moonshot"
+	PHOENIX             SwapType = "Phoenix"
+	OPENBOOK            SwapType = "OpenBookV2"
+	SOLFI               SwapType = "SolFi"
+	OBRIC               SwapType = "Obric"
+	ZEROFI              SwapType = "ZeroFi"
+	HUMIDIFI            SwapType = "HumidiFi"
+	LIFINITY            SwapType = "Lifinity"
+	SANCTUM             SwapType = "Sanctum"
+	SABER               SwapType = "Saber"
+	MERCURIAL           SwapType = "Mercurial"
+	STABBLE             SwapType = "Stabble"
+	SANCTUM_INFINITY    SwapType = "SanctumInfinity"
+	UNKNOWN             SwapType = "Unknown"
 )
+
+// ammNames maps venue program ids to the name reported in SwapInfo
+var ammNames = map[solana.PublicKey]SwapType{
+	PUMP_FUN_PROGRAM_ID:                       PUMP_FUN,
+	PUMP_AMM_PROGRAM_ID:                       PUMP_SWAP,
+	PHOENIX_PROGRAM_ID:                        PHOENIX,
+	OPENBOOK_V2_PROGRAM_ID:                    OPENBOOK,
+	SOLFI_PROGRAM_ID:                          SOLFI,
+	OBRIC_V2_PROGRAM_ID:                       OBRIC,
+	ZEROFI_PROGRAM_ID:                         ZEROFI,
+	HUMIDIFI_PROGRAM_ID:                       HUMIDIFI,
+	LIFINITY_V2_PROGRAM_ID:                    LIFINITY,
+	SANCTUM_INFINITY_PROGRAM_ID:               SANCTUM_INFINITY,
+	SABER_STABLE_SWAP_PROGRAM_ID:              SABER,
+	MERCURIAL_PROGRAM_ID:                      MERCURIAL,
+	STABBLE_STABLE_SWAP_PROGRAM_ID:            STABBLE,
+	STABBLE_WEIGHTED_SWAP_PROGRAM_ID:          STABBLE,
+	SANCTUM_ROUTER_PROGRAM_ID:                 SANCTUM,
+	SPL_STAKE_POOL_PROGRAM_ID:                 SANCTUM,
+	SANCTUM_SPL_STAKE_POOL_PROGRAM_ID:         SANCTUM,
+	SANCTUM_MULTI_STAKE_POOL_PROGRAM_ID:       SANCTUM,
+	RAYDIUM_V4_PROGRAM_ID:                     RAYDIUM,
+	RAYDIUM_AMM_PROGRAM_ID:                    RAYDIUM,
+	RAYDIUM_CPMM_PROGRAM_ID:                   RAYDIUM,
+	RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID: RAYDIUM,
+	RAYDIUM_Launchpad_PROGRAM_ID:              RAYDIUM_Launchpad,
+	METEORA_PROGRAM_ID:                        METEORA,
+	METEORA_POOLS_PROGRAM_ID:                  METEORA,
+	METEORA_DBC_PROGRAM_ID:                    METEORA_DBC,
+	MOONSHOT_PROGRAM_ID:                       MOONSHOT,
+	ORCA_PROGRAM_ID:                           ORCA,
+}
+
+// ammName returns the venue name of a program id, UNKNOWN if it is not a known venue
+func ammName(programID solana.PublicKey) SwapType {
+	if name, ok := ammNames[programID]; ok {
+		return name
+	}
+	return UNKNOWN
+}
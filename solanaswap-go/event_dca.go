@@ -0,0 +1,150 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	DCAOpenedEventDiscriminator       = [8]byte{166, 172, 97, 9, 77, 76, 189, 109}
+	DCAClosedEventDiscriminator       = [8]byte{50, 31, 87, 155, 135, 220, 195, 239}
+	DCAFilledEventDiscriminator       = [8]byte{134, 4, 17, 63, 221, 45, 177, 173}
+	DCACollectedFeeEventDiscriminator = [8]byte{42, 136, 216, 116, 181, 209, 109, 181}
+	DCAWithdrawEventDiscriminator     = [8]byte{192, 241, 201, 217, 70, 150, 90, 247}
+	DCADepositEventDiscriminator      = [8]byte{62, 205, 242, 175, 244, 169, 136, 52}
+)
+
+type DCAOpenedEvent struct {
+	UserKey          solana.PublicKey
+	DcaKey           solana.PublicKey
+	InDeposited      uint64
+	InputMint        solana.PublicKey
+	OutputMint       solana.PublicKey
+	CycleFrequency   int64
+	InAmountPerCycle uint64
+	CreatedAt        int64
+}
+
+type DCAClosedEvent struct {
+	UserKey           solana.PublicKey
+	DcaKey            solana.PublicKey
+	InDeposited       uint64
+	InputMint         solana.PublicKey
+	OutputMint        solana.PublicKey
+	CycleFrequency    int64
+	InAmountPerCycle  uint64
+	CreatedAt         int64
+	TotalInWithdrawn  uint64
+	TotalOutWithdrawn uint64
+	UnfilledAmount    uint64
+	UserClosed        bool
+}
+
+type DCAFilledEvent struct {
+	UserKey    solana.PublicKey
+	DcaKey     solana.PublicKey
+	InputMint  solana.PublicKey
+	OutputMint solana.PublicKey
+	InAmount   uint64
+	OutAmount  uint64
+	FeeMint    solana.PublicKey
+	Fee        uint64
+}
+
+type DCACollectedFeeEvent struct {
+	UserKey solana.PublicKey
+	DcaKey  solana.PublicKey
+	Mint    solana.PublicKey
+	Amount  uint64
+}
+
+type DCAWithdrawEvent struct {
+	DcaKey       solana.PublicKey
+	InAmount     uint64
+	OutAmount    uint64
+	UserWithdraw bool
+}
+
+type DCADepositEvent struct {
+	DcaKey solana.PublicKey
+	Amount uint64
+}
+
+// DCAEvent is a decoded Jupiter DCA event, Data holds one of the DCA*Event types
+type DCAEvent struct {
+	Name string
+	Data interface{}
+}
+
+var dcaEventTypes = []struct {
+	discriminator [8]byte
+	name          string
+	new           func() interface{}
+}{
+	{DCAOpenedEventDiscriminator, "Opened", func() interface{} { return &DCAOpenedEvent{} }},
+	{DCAClosedEventDiscriminator, "Closed", func() interface{} { return &DCAClosedEvent{} }},
+	{DCAFilledEventDiscriminator, "Filled", func() interface{} { return &DCAFilledEvent{} }},
+	{DCACollectedFeeEventDiscriminator, "CollectedFee", func() interface{} { return &DCACollectedFeeEvent{} }},
+	{DCAWithdrawEventDiscriminator, "Withdraw", func() interface{} { return &DCAWithdrawEvent{} }},
+	{DCADepositEventDiscriminator, "Deposit", func() interface{} { return &DCADepositEvent{} }},
+}
+
+// ParseDCAEvents returns the Jupiter DCA events of the transaction, including open, close, deposit and withdraw
+func (p *Parser) ParseDCAEvents() []DCAEvent {
+	var events []DCAEvent
+	for i := range p.txInfo.Message.Instructions {
+		if !p.allAccountKeys[p.txInfo.Message.Instructions[i].ProgramIDIndex].Equals(JUPITER_DCA_PROGRAM_ID) &&
+			!p.innerContainsProgram(i, JUPITER_DCA_PROGRAM_ID) {
+			continue
+		}
+		events = append(events, p.decodeDCAEvents(i)...)
+	}
+	return events
+}
+
+// processDCASwaps returns the DCA fills of an outer instruction, the swap itself is done by a separate Jupiter route
+func (p *Parser) processDCASwaps(instructionIndex int) []SwapData {
+	var swaps []SwapData
+	for _, event := range p.decodeDCAEvents(instructionIndex) {
+		if fill, ok := event.Data.(*DCAFilledEvent); ok {
+			swaps = append(swaps, SwapData{Type: JUPITER_DCA, Data: fill})
+		}
+	}
+	return swaps
+}
+
+func (p *Parser) decodeDCAEvents(instructionIndex int) []DCAEvent {
+	var events []DCAEvent
+	for _, data := range p.anchorEvents(JUPITER_DCA_PROGRAM_ID, instructionIndex) {
+		event, err := DecodeDCAEvent(data)
+		if err != nil {
+			p.Log.Errorf("error unmarshaling DCA event: %s", err)
+			continue
+		}
+		if event != nil {
+			events = append(events, *event)
+		}
+	}
+	return events
+}
+
+// DecodeDCAEvent decodes a Jupiter DCA event starting at its discriminator, it returns nil for other events
+func DecodeDCAEvent(data []byte) (*DCAEvent, error) {
+	if len(data) < 8 {
+		return nil, nil
+	}
+	for _, eventType := range dcaEventTypes {
+		if !bytes.Equal(data[:8], eventType.discriminator[:]) {
+			continue
+		}
+		event := eventType.new()
+		if err := ag_binary.NewBorshDecoder(data[8:]).Decode(event); err != nil {
+			return nil, fmt.Errorf("%s: %w", eventType.name, err)
+		}
+		return &DCAEvent{Name: eventType.name, Data: event}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,61 @@
+package solanaswapgo
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/lonelybeanz/solanaswap-go/idl"
+)
+
+// eventDecoderPrograms are the programs whose events the parser decodes itself, their registered IDLs are
+// only used to name instructions
+var eventDecoderPrograms = []solana.PublicKey{
+	JUPITER_PROGRAM_ID,
+	JUPITER_DCA_PROGRAM_ID,
+	JUPITER_LIMIT_ORDER_PROGRAM_ID,
+	JUPITER_LIMIT_ORDER_V2_PROGRAM_ID,
+	PUMP_FUN_PROGRAM_ID,
+	PUMP_AMM_PROGRAM_ID,
+	RAYDIUM_Launchpad_PROGRAM_ID,
+	METEORA_DBC_PROGRAM_ID,
+	MOONSHOT_PROGRAM_ID,
+	OPENBOOK_V2_PROGRAM_ID,
+}
+
+// IDLEvent is an event decoded with the registered IDL of its program
+type IDLEvent struct {
+	ProgramID  solana.PublicKey
+	OuterIndex int
+	Event      *idl.DecodedEvent
+}
+
+// ParseIDLEvents decodes the self-CPI and "Program data:" events of the programs without a decoder in the
+// parser, with the IDLs registered in the idl package
+func (p *Parser) ParseIDLEvents() []IDLEvent {
+	var events []IDLEvent
+	for i, outerInstruction := range p.txInfo.Message.Instructions {
+		programIDs := []solana.PublicKey{p.allAccountKeys[outerInstruction.ProgramIDIndex]}
+		for _, inner := range p.getInnerInstructions(i) {
+			if programID := p.allAccountKeys[inner.ProgramIDIndex]; !containsPublicKey(programIDs, programID) {
+				programIDs = append(programIDs, programID)
+			}
+		}
+
+		for _, programID := range programIDs {
+			registered := idl.Lookup(programID)
+			if registered == nil || containsPublicKey(eventDecoderPrograms, programID) {
+				continue
+			}
+			for _, data := range p.anchorEvents(programID, i) {
+				if registered.EventName(data) == "" {
+					continue
+				}
+				event, err := registered.DecodeEvent(data)
+				if err != nil {
+					p.Log.Errorf("error decoding %s event with its IDL: %s", programID, err)
+					continue
+				}
+				events = append(events, IDLEvent{ProgramID: programID, OuterIndex: i, Event: event})
+			}
+		}
+	}
+	return events
+}
@@ -1,8 +1,10 @@
 package solanaswapgo
 
 import (
-	"encoding/json"
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"strings"
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
@@ -24,28 +26,190 @@ type JupiterSwapEventData struct {
 	OutputMintDecimals uint8
 }
 
-var JupiterRouteEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 64, 198, 205, 232, 38, 8, 113, 226}
+// JupiterSwapEventV2 is a hop of the SwapsEvent, it no longer carries the amm
+type JupiterSwapEventV2 struct {
+	InputMint    solana.PublicKey
+	InputAmount  uint64
+	OutputMint   solana.PublicKey
+	OutputAmount uint64
+}
+
+type JupiterSwapsEvent struct {
+	SwapEvents []JupiterSwapEventV2
+}
+
+type JupiterFeeEvent struct {
+	Account solana.PublicKey
+	Mint    solana.PublicKey
+	Amount  uint64
+}
 
+// JupiterRouteArgs holds the fixed arguments that follow the route plan of a route instruction
+type JupiterRouteArgs struct {
+	Instruction     string
+	InAmount        uint64
+	QuotedOutAmount uint64
+	OutAmount       uint64
+	QuotedInAmount  uint64
+	SlippageBps     uint16
+	PlatformFeeBps  uint8
+}
+
+var (
+	JupiterRouteEventDiscriminator        = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 64, 198, 205, 232, 38, 8, 113, 226}
+	JupiterSwapsEventDiscriminator        = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 152, 47, 78, 235, 192, 96, 110, 106}
+	JupiterFeeEventDiscriminator          = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 73, 79, 78, 127, 184, 213, 13, 220}
+	JupiterRouteDiscriminator             = [8]byte{229, 23, 203, 151, 122, 227, 173, 42}
+	JupiterSharedRouteDiscriminator       = [8]byte{193, 32, 155, 51, 65, 214, 156, 129}
+	JupiterExactOutDiscriminator          = [8]byte{208, 51, 239, 151, 123, 43, 237, 92}
+	JupiterSharedExactOutDiscriminator    = [8]byte{176, 209, 105, 168, 154, 125, 69, 62}
+	JupiterTokenLedgerDiscriminator       = [8]byte{150, 86, 71, 116, 167, 93, 14, 104}
+	JupiterSharedTokenLedgerDiscriminator = [8]byte{230, 121, 143, 80, 119, 159, 106, 170}
+)
+
+// jupiterRouteInstructions maps the route discriminators to their name and the size of the trailing fixed args
+var jupiterRouteInstructions = []struct {
+	discriminator [8]byte
+	name          string
+	argsLen       int
+}{
+	{JupiterRouteDiscriminator, "route", 19},
+	{JupiterSharedRouteDiscriminator, "shared_accounts_route", 19},
+	{JupiterExactOutDiscriminator, "exact_out_route", 19},
+	{JupiterSharedExactOutDiscriminator, "shared_accounts_exact_out_route", 19},
+	{JupiterTokenLedgerDiscriminator, "route_with_token_ledger", 11},
+	{JupiterSharedTokenLedgerDiscriminator, "shared_accounts_route_with_token_ledger", 11},
+}
+
+// processJupiterSwaps processes a Jupiter route, the outer instruction is either Jupiter or a program calling it through CPI
 func (p *Parser) processJupiterSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isJupiterRouteEventInstruction(innerInstruction) {
-					eventData, err := p.parseJupiterRouteEventInstruction(innerInstruction)
-					if err != nil {
-						p.Log.Errorf("error processing Pumpfun trade event: %s", err)
-					}
-					if eventData != nil {
-						swaps = append(swaps, SwapData{Type: JUPITER, Data: eventData})
-					}
-				}
+
+	outerInstruction := p.txInfo.Message.Instructions[instructionIndex]
+	if args := p.parseJupiterRouteArgs(outerInstruction); args != nil {
+		swaps = append(swaps, SwapData{Type: JUPITER, Data: args})
+	}
+
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		if !p.allAccountKeys[innerInstruction.ProgramIDIndex].Equals(JUPITER_PROGRAM_ID) {
+			continue
+		}
+		decodedBytes, err := base58.Decode(innerInstruction.Data.String())
+		if err != nil {
+			p.Log.Errorf("error decoding Jupiter instruction data: %s", err)
+			continue
+		}
+		if len(decodedBytes) < 16 {
+			continue
+		}
+
+		switch {
+		case bytes.Equal(decodedBytes[:16], JupiterRouteEventDiscriminator[:]):
+			eventData, err := p.parseJupiterRouteEventInstruction(innerInstruction)
+			if err != nil {
+				p.Log.Errorf("error processing Jupiter swap event: %s", err)
+				continue
+			}
+			swaps = append(swaps, SwapData{Type: JUPITER, Data: eventData})
+		case bytes.Equal(decodedBytes[:16], JupiterSwapsEventDiscriminator[:]):
+			var event JupiterSwapsEvent
+			if err := ag_binary.NewBorshDecoder(decodedBytes[16:]).Decode(&event); err != nil {
+				p.Log.Errorf("error unmarshaling Jupiter SwapsEvent: %s", err)
+				continue
+			}
+			for _, swapEvent := range event.SwapEvents {
+				swaps = append(swaps, SwapData{Type: JUPITER, Data: &JupiterSwapEventData{
+					JupiterSwapEvent: JupiterSwapEvent{
+						InputMint:    swapEvent.InputMint,
+						InputAmount:  swapEvent.InputAmount,
+						OutputMint:   swapEvent.OutputMint,
+						OutputAmount: swapEvent.OutputAmount,
+					},
+					InputMintDecimals:  p.splDecimalsMap[swapEvent.InputMint.String()],
+					OutputMintDecimals: p.splDecimalsMap[swapEvent.OutputMint.String()],
+				}})
+			}
+		case bytes.Equal(decodedBytes[:16], JupiterFeeEventDiscriminator[:]):
+			var event JupiterFeeEvent
+			if err := ag_binary.NewBorshDecoder(decodedBytes[16:]).Decode(&event); err != nil {
+				p.Log.Errorf("error unmarshaling Jupiter FeeEvent: %s", err)
+				continue
+			}
+			swaps = append(swaps, SwapData{Type: JUPITER, Data: &event})
+		default:
+			// Jupiter called through CPI
+			if args := p.parseJupiterRouteArgs(p.convertRPCToSolanaInstruction(innerInstruction)); args != nil {
+				swaps = append(swaps, SwapData{Type: JUPITER, Data: args})
 			}
 		}
 	}
+
+	if p.innerContainsProgram(instructionIndex, PHOENIX_PROGRAM_ID) {
+		swaps = append(swaps, p.processPhoenixSwaps(instructionIndex)...)
+	}
+	if p.innerContainsProgram(instructionIndex, OPENBOOK_V2_PROGRAM_ID) {
+		swaps = append(swaps, p.processOpenbookSwaps(instructionIndex)...)
+	}
+	for _, layout := range propAmmLayouts {
+		if p.innerContainsProgram(instructionIndex, layout.ProgramID) {
+			swaps = append(swaps, p.processPropAmmSwaps(instructionIndex)...)
+			break
+		}
+	}
+	for _, layout := range stableSwapLayouts {
+		if p.innerContainsProgram(instructionIndex, layout.ProgramID) {
+			swaps = append(swaps, p.processStableSwaps(instructionIndex)...)
+			break
+		}
+	}
+	for _, programID := range append([]solana.PublicKey{SANCTUM_INFINITY_PROGRAM_ID}, stakePoolPrograms...) {
+		if p.innerContainsProgram(instructionIndex, programID) {
+			swaps = append(swaps, p.processSanctumSwaps(instructionIndex)...)
+			break
+		}
+	}
 	return swaps
 }
 
+// parseJupiterRouteArgs decodes the args of a route instruction, they follow the variable length route plan
+func (p *Parser) parseJupiterRouteArgs(inst solana.CompiledInstruction) *JupiterRouteArgs {
+	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(JUPITER_PROGRAM_ID) || len(inst.Data) < 8 {
+		return nil
+	}
+	decodedBytes, err := base58.Decode(inst.Data.String())
+	if err != nil {
+		p.Log.Errorf("error decoding Jupiter instruction data: %s", err)
+		return nil
+	}
+
+	for _, route := range jupiterRouteInstructions {
+		if !bytes.Equal(decodedBytes[:8], route.discriminator[:]) {
+			continue
+		}
+		if len(decodedBytes) < 8+route.argsLen {
+			return nil
+		}
+		tail := decodedBytes[len(decodedBytes)-route.argsLen:]
+		args := &JupiterRouteArgs{Instruction: route.name}
+		switch route.argsLen {
+		case 19:
+			if strings.Contains(route.name, "exact_out") {
+				args.OutAmount = binary.LittleEndian.Uint64(tail[0:8])
+				args.QuotedInAmount = binary.LittleEndian.Uint64(tail[8:16])
+			} else {
+				args.InAmount = binary.LittleEndian.Uint64(tail[0:8])
+				args.QuotedOutAmount = binary.LittleEndian.Uint64(tail[8:16])
+			}
+		case 11:
+			args.QuotedOutAmount = binary.LittleEndian.Uint64(tail[0:8])
+		}
+		args.SlippageBps = binary.LittleEndian.Uint16(tail[len(tail)-3 : len(tail)-1])
+		args.PlatformFeeBps = tail[len(tail)-1]
+		return args
+	}
+	return nil
+}
+
 // containsDCAProgram checks if the transaction contains the Jupiter DCA program.
 func (p *Parser) containsDCAProgram() bool {
 	for _, accountKey := range p.allAccountKeys {
@@ -148,41 +312,80 @@ func parseJupiterEvents(events []SwapData) (*SwapInfo, error) {
 	}
 
 	var firstSwap, lastSwap *JupiterSwapEventData
+	var legs []SwapLeg
+	var fees []SwapFee
+	var args *JupiterRouteArgs
 
-	for i, event := range events {
+	for _, event := range events {
 		if event.Type != JUPITER {
 			continue
 		}
 
-		var jupiterEvent JupiterSwapEventData
-		eventData, err := json.Marshal(event.Data)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal event data: %v", err)
-		}
-
-		if err := json.Unmarshal(eventData, &jupiterEvent); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal Jupiter event data: %v", err)
-		}
-
-		if i == 0 {
-			firstSwap = &jupiterEvent
+		switch data := event.Data.(type) {
+		case *JupiterSwapEventData:
+			if firstSwap == nil {
+				firstSwap = data
+			}
+			lastSwap = data
+
+			legs = append(legs, SwapLeg{
+				AMM:              string(ammName(data.Amm)),
+				ProgramID:        data.Amm,
+				TokenInMint:      data.InputMint,
+				TokenInAmount:    data.InputAmount,
+				TokenInDecimals:  data.InputMintDecimals,
+				TokenOutMint:     data.OutputMint,
+				TokenOutAmount:   data.OutputAmount,
+				TokenOutDecimals: data.OutputMintDecimals,
+			})
+		case *JupiterFeeEvent:
+			fees = append(fees, SwapFee{Type: "platform", Mint: data.Mint, Amount: data.Amount, Recipient: data.Account})
+		case *JupiterRouteArgs:
+			if args == nil {
+				args = data
+			}
 		}
-		lastSwap = &jupiterEvent
 	}
 
 	if firstSwap == nil || lastSwap == nil {
 		return nil, fmt.Errorf("no valid Jupiter swaps found")
 	}
 
+	// split routes spread the amounts over several events
+	amountIn, amountOut := routeAmounts(legs)
 	swapInfo := &SwapInfo{
 		AMMs:             []string{string(JUPITER)},
 		TokenInMint:      firstSwap.InputMint,
-		TokenInAmount:    firstSwap.InputAmount,
+		TokenInAmount:    amountIn,
 		TokenInDecimals:  firstSwap.InputMintDecimals,
 		TokenOutMint:     lastSwap.OutputMint,
-		TokenOutAmount:   lastSwap.OutputAmount,
+		TokenOutAmount:   amountOut,
 		TokenOutDecimals: lastSwap.OutputMintDecimals,
+		Legs:             legs,
+		Fees:             fees,
+	}
+
+	if args != nil {
+		// the args hold the exact totals of the side the route fixed
+		if args.InAmount > 0 {
+			swapInfo.TokenInAmount = args.InAmount
+		}
+		if args.OutAmount > 0 {
+			swapInfo.TokenOutAmount = args.OutAmount
+		}
+		swapInfo.RouteData = &RouteData{
+			RouteType: string(JUPITER),
+			Data:      args,
+		}
 	}
 
 	return swapInfo, nil
 }
+
+// jupiterLegMatches checks whether a venue leg is the one reported by a Jupiter event
+func jupiterLegMatches(routeLeg SwapLeg, venueLeg SwapLeg) bool {
+	if !routeLeg.ProgramID.IsZero() {
+		return routeLeg.ProgramID.Equals(venueLeg.ProgramID)
+	}
+	return routeLeg.TokenInMint.Equals(venueLeg.TokenInMint) && routeLeg.TokenOutMint.Equals(venueLeg.TokenOutMint)
+}
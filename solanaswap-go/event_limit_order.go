@@ -0,0 +1,280 @@
+package solanaswapgo
+
+import (
+	"bytes"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/mr-tron/base58"
+)
+
+var (
+	LimitOrderTradeEventDiscriminator       = [8]byte{189, 219, 127, 211, 78, 230, 97, 238}
+	LimitOrderCreateOrderEventDiscriminator = [8]byte{49, 142, 72, 166, 230, 29, 84, 84}
+	LimitOrderCancelOrderEventDiscriminator = [8]byte{174, 66, 141, 17, 4, 224, 162, 77}
+	LimitOrderFillOrderDiscriminator        = [8]byte{232, 122, 115, 25, 199, 143, 136, 162}
+	LimitOrderFlashFillOrderDiscriminator   = [8]byte{252, 104, 18, 134, 164, 78, 18, 140}
+)
+
+// LimitOrderTradeEvent is the v1 fill event, amounts are in input and output mint units
+type LimitOrderTradeEvent struct {
+	OrderKey           solana.PublicKey
+	Taker              solana.PublicKey
+	RemainingInAmount  uint64
+	RemainingOutAmount uint64
+	InAmount           uint64
+	OutAmount          uint64
+}
+
+// LimitOrderV2TradeEvent is the v2 fill event
+type LimitOrderV2TradeEvent struct {
+	OrderKey              solana.PublicKey
+	Taker                 solana.PublicKey
+	RemainingMakingAmount uint64
+	RemainingTakingAmount uint64
+	MakingAmount          uint64
+	TakingAmount          uint64
+}
+
+type LimitOrderCreateOrderEvent struct {
+	OrderKey   solana.PublicKey
+	Maker      solana.PublicKey
+	InputMint  solana.PublicKey
+	OutputMint solana.PublicKey
+	InAmount   uint64
+	OutAmount  uint64
+	ExpiredAt  *int64 `bin:"optional"`
+}
+
+type LimitOrderV2CreateOrderEvent struct {
+	OrderKey           solana.PublicKey
+	Maker              solana.PublicKey
+	InputMint          solana.PublicKey
+	OutputMint         solana.PublicKey
+	InputTokenProgram  solana.PublicKey
+	OutputTokenProgram solana.PublicKey
+	MakingAmount       uint64
+	TakingAmount       uint64
+	ExpiredAt          *int64 `bin:"optional"`
+	FeeBps             uint16
+	FeeAccount         solana.PublicKey
+}
+
+type LimitOrderCancelOrderEvent struct {
+	OrderKey solana.PublicKey
+}
+
+// LimitOrderEvent is a decoded limit order event, Data holds one of the LimitOrder*Event types
+type LimitOrderEvent struct {
+	Name    string
+	Version int
+	Data    interface{}
+}
+
+// LimitOrderFill is a keeper fill of a limit order seen from the maker, who sells the input mint for the output mint
+type LimitOrderFill struct {
+	Version               int
+	Order                 solana.PublicKey
+	Maker                 solana.PublicKey
+	Taker                 solana.PublicKey
+	InputMint             solana.PublicKey
+	InputDecimals         uint8
+	OutputMint            solana.PublicKey
+	OutputDecimals        uint8
+	MakingAmount          uint64
+	TakingAmount          uint64
+	RemainingMakingAmount uint64
+	RemainingTakingAmount uint64
+	Fees                  []SwapFee
+}
+
+func isLimitOrderProgram(programID solana.PublicKey) bool {
+	return programID.Equals(JUPITER_LIMIT_ORDER_PROGRAM_ID) || programID.Equals(JUPITER_LIMIT_ORDER_V2_PROGRAM_ID)
+}
+
+// ParseLimitOrderEvents returns the Jupiter limit order events of the transaction, including order creation and cancellation
+func (p *Parser) ParseLimitOrderEvents() []LimitOrderEvent {
+	var events []LimitOrderEvent
+	for i, outerInstruction := range p.txInfo.Message.Instructions {
+		for _, programID := range []solana.PublicKey{JUPITER_LIMIT_ORDER_PROGRAM_ID, JUPITER_LIMIT_ORDER_V2_PROGRAM_ID} {
+			if p.allAccountKeys[outerInstruction.ProgramIDIndex].Equals(programID) || p.innerContainsProgram(i, programID) {
+				events = append(events, p.decodeLimitOrderEvents(programID, i)...)
+			}
+		}
+	}
+	return events
+}
+
+func (p *Parser) decodeLimitOrderEvents(programID solana.PublicKey, instructionIndex int) []LimitOrderEvent {
+	version := 1
+	if programID.Equals(JUPITER_LIMIT_ORDER_V2_PROGRAM_ID) {
+		version = 2
+	}
+
+	var events []LimitOrderEvent
+	for _, data := range p.anchorEvents(programID, instructionIndex) {
+		if len(data) < 8 {
+			continue
+		}
+		var name string
+		var event interface{}
+		switch {
+		case bytes.Equal(data[:8], LimitOrderTradeEventDiscriminator[:]) && version == 1:
+			name, event = "Trade", &LimitOrderTradeEvent{}
+		case bytes.Equal(data[:8], LimitOrderTradeEventDiscriminator[:]):
+			name, event = "Trade", &LimitOrderV2TradeEvent{}
+		case bytes.Equal(data[:8], LimitOrderCreateOrderEventDiscriminator[:]) && version == 1:
+			name, event = "CreateOrder", &LimitOrderCreateOrderEvent{}
+		case bytes.Equal(data[:8], LimitOrderCreateOrderEventDiscriminator[:]):
+			name, event = "CreateOrder", &LimitOrderV2CreateOrderEvent{}
+		case bytes.Equal(data[:8], LimitOrderCancelOrderEventDiscriminator[:]):
+			name, event = "CancelOrder", &LimitOrderCancelOrderEvent{}
+		default:
+			continue
+		}
+		if err := ag_binary.NewBorshDecoder(data[8:]).Decode(event); err != nil {
+			p.Log.Errorf("error unmarshaling limit order %s event: %s", name, err)
+			continue
+		}
+		events = append(events, LimitOrderEvent{Name: name, Version: version, Data: event})
+	}
+	return events
+}
+
+// processLimitOrderSwaps returns the limit order fills of an outer instruction
+func (p *Parser) processLimitOrderSwaps(instructionIndex int) []SwapData {
+	var swaps []SwapData
+	for _, programID := range []solana.PublicKey{JUPITER_LIMIT_ORDER_PROGRAM_ID, JUPITER_LIMIT_ORDER_V2_PROGRAM_ID} {
+		for _, event := range p.decodeLimitOrderEvents(programID, instructionIndex) {
+			var fill *LimitOrderFill
+			switch trade := event.Data.(type) {
+			case *LimitOrderTradeEvent:
+				fill = &LimitOrderFill{
+					Version:               1,
+					Order:                 trade.OrderKey,
+					Taker:                 trade.Taker,
+					MakingAmount:          trade.InAmount,
+					TakingAmount:          trade.OutAmount,
+					RemainingMakingAmount: trade.RemainingInAmount,
+					RemainingTakingAmount: trade.RemainingOutAmount,
+				}
+			case *LimitOrderV2TradeEvent:
+				fill = &LimitOrderFill{
+					Version:               2,
+					Order:                 trade.OrderKey,
+					Taker:                 trade.Taker,
+					MakingAmount:          trade.MakingAmount,
+					TakingAmount:          trade.TakingAmount,
+					RemainingMakingAmount: trade.RemainingMakingAmount,
+					RemainingTakingAmount: trade.RemainingTakingAmount,
+				}
+			default:
+				continue
+			}
+			p.resolveLimitOrderFill(programID, instructionIndex, fill)
+			swaps = append(swaps, SwapData{Type: JUPITER_LIMIT_ORDER, Data: fill})
+		}
+	}
+	return swaps
+}
+
+// resolveLimitOrderFill fills in the maker from the fill instruction, and the mints and fees from the token transfers
+// the fill instruction issues
+func (p *Parser) resolveLimitOrderFill(programID solana.PublicKey, instructionIndex int, fill *LimitOrderFill) {
+	// v1 fill_order: order, reserve, maker, taker, taker output, maker output, taker input, fee authority,
+	// program fee account, referral, ... / v2 flash_fill_order: taker, maker, order, input mint reserve,
+	// maker output, fee account, ...
+	makerIndex, reserveIndex, feeIndexes := 2, 1, []int{8, 9}
+	if fill.Version == 2 {
+		makerIndex, reserveIndex, feeIndexes = 1, 3, []int{5}
+	}
+
+	// without stack heights the transfers of the fill cannot be told apart from those of a route or of other
+	// fills, only the maker payout is looked for then
+	var fillInstruction *solana.CompiledInstruction
+	var transfers []solana.CompiledInstruction
+	scoped := false
+	if root, ok := p.invocationTree(instructionIndex); ok {
+		scoped = true
+		root.walk(func(node *invocation) {
+			if fillInstruction != nil || !node.ProgramID.Equals(programID) || !p.isLimitOrderFill(node.Instruction, fill.Order) {
+				return
+			}
+			fillInstruction = &node.Instruction
+			for _, child := range node.Children {
+				transfers = append(transfers, child.Instruction)
+			}
+		})
+	} else {
+		instructions := []solana.CompiledInstruction{p.txInfo.Message.Instructions[instructionIndex]}
+		for _, inner := range p.getInnerInstructions(instructionIndex) {
+			instructions = append(instructions, p.convertRPCToSolanaInstruction(inner))
+		}
+		for i := range instructions {
+			if p.allAccountKeys[instructions[i].ProgramIDIndex].Equals(programID) && p.isLimitOrderFill(instructions[i], fill.Order) {
+				fillInstruction = &instructions[i]
+				break
+			}
+		}
+		transfers = instructions[1:]
+	}
+	if fillInstruction == nil {
+		return
+	}
+	fill.Maker = p.allAccountKeys[fillInstruction.Accounts[makerIndex]]
+	fill.InputMint, fill.InputDecimals = p.tokenAccountMint(p.allAccountKeys[fillInstruction.Accounts[reserveIndex]])
+
+	var feeAccounts []solana.PublicKey
+	for _, index := range feeIndexes {
+		if index < len(fillInstruction.Accounts) {
+			feeAccounts = append(feeAccounts, p.allAccountKeys[fillInstruction.Accounts[index]])
+		}
+	}
+
+	// the taker pays the maker in the output mint, the program fee and referral accounts get the fees
+	for _, inst := range transfers {
+		transfer := p.parseTokenMovement(inst)
+		if transfer == nil {
+			continue
+		}
+		mint := solana.MustPublicKeyFromBase58(transfer.mint)
+		destination := solana.MustPublicKeyFromBase58(transfer.destination)
+		owner := p.tokenAccountOwner(destination)
+		switch {
+		case fill.Maker.IsZero():
+		case owner.Equals(fill.Maker) && !mint.Equals(fill.InputMint):
+			fill.OutputMint, fill.OutputDecimals = mint, transfer.decimals
+		case scoped && (containsPublicKey(feeAccounts, destination) || (!owner.IsZero() && containsPublicKey(feeAccounts, owner))):
+			fill.Fees = append(fill.Fees, SwapFee{
+				Type:      "limit_order",
+				Mint:      mint,
+				Amount:    transfer.amount,
+				Recipient: destination,
+			})
+		}
+	}
+}
+
+// isLimitOrderFill checks whether inst is a fill_order or flash_fill_order of order
+func (p *Parser) isLimitOrderFill(inst solana.CompiledInstruction, order solana.PublicKey) bool {
+	// both layouts start with order, reserve, maker and taker in some order
+	if len(inst.Accounts) < 4 {
+		return false
+	}
+	decodedBytes, err := base58.Decode(inst.Data.String())
+	if err != nil || len(decodedBytes) < 8 || (!bytes.Equal(decodedBytes[:8], LimitOrderFillOrderDiscriminator[:]) &&
+		!bytes.Equal(decodedBytes[:8], LimitOrderFlashFillOrderDiscriminator[:])) {
+		return false
+	}
+	return p.instructionReferences(inst, order)
+}
+
+// instructionReferences checks whether the instruction takes account as one of its accounts
+func (p *Parser) instructionReferences(inst solana.CompiledInstruction, account solana.PublicKey) bool {
+	for _, index := range inst.Accounts {
+		if p.allAccountKeys[index].Equals(account) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,152 +4,452 @@ import (
 	"bytes"
 	"fmt"
 
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/mr-tron/base58"
 )
 
 var (
-	OKX_SWAP_DISCRIMINATOR                 = [8]byte{248, 198, 158, 145, 225, 117, 135, 200}
-	OKX_SWAP2_DISCRIMINATOR                = [8]byte{65, 75, 63, 76, 235, 91, 91, 136}
-	OKX_COMMISSION_SPL_SWAP2_DISCRIMINATOR = [8]byte{173, 131, 78, 38, 150, 165, 123, 15}
+	OKX_SWAP_DISCRIMINATOR                      = [8]byte{248, 198, 158, 145, 225, 117, 135, 200}
+	OKX_SWAP2_DISCRIMINATOR                     = [8]byte{65, 75, 63, 76, 235, 91, 91, 136}
+	OKX_PROXY_SWAP_DISCRIMINATOR                = [8]byte{19, 44, 130, 148, 72, 56, 44, 238}
+	OKX_COMMISSION_SPL_SWAP_DISCRIMINATOR       = [8]byte{235, 71, 211, 196, 114, 199, 143, 92}
+	OKX_COMMISSION_SOL_SWAP_DISCRIMINATOR       = [8]byte{81, 128, 134, 73, 114, 73, 45, 94}
+	OKX_COMMISSION_SPL_PROXY_SWAP_DISCRIMINATOR = [8]byte{96, 67, 12, 151, 129, 164, 18, 71}
+	OKX_COMMISSION_SOL_PROXY_SWAP_DISCRIMINATOR = [8]byte{30, 33, 208, 91, 31, 157, 37, 18}
+	OKX_COMMISSION_SPL_SWAP2_DISCRIMINATOR      = [8]byte{173, 131, 78, 38, 150, 165, 123, 15}
+	OKX_COMMISSION_SOL_SWAP2_DISCRIMINATOR      = [8]byte{113, 132, 31, 74, 99, 169, 57, 146}
+	OKX_PLATFORM_FEE_SPL_PROXY_SWAP_V2          = [8]byte{69, 164, 210, 89, 146, 214, 173, 67}
+	OKX_PLATFORM_FEE_SOL_PROXY_SWAP_V2          = [8]byte{69, 200, 254, 247, 40, 52, 118, 202}
 )
 
-func (p *Parser) processOKXSwaps(instructionIndex int) []SwapData {
+// okxCommission is how an OKX instruction encodes its commission after the SwapArgs
+type okxCommission int
 
-	parentInstruction := p.txInfo.Message.Instructions[instructionIndex]
-	programID := p.allAccountKeys[parentInstruction.ProgramIDIndex]
+const (
+	okxNoCommission   okxCommission = iota
+	okxCommissionRate               // commission_rate: u16, commission_direction: bool
+	okxCommissionInfo               // commission_info: u32, direction in the top bit
+	okxPlatformFee                  // commission_info: u32, platform_fee_rate: u16, order_id: u64
+)
 
-	if !programID.Equals(OKX_DEX_ROUTER_PROGRAM_ID) {
-		return nil
+var okxInstructions = []struct {
+	discriminator [8]byte
+	name          string
+	commission    okxCommission
+}{
+	{OKX_SWAP_DISCRIMINATOR, "swap", okxNoCommission},
+	{OKX_SWAP2_DISCRIMINATOR, "swap2", okxNoCommission},
+	{OKX_PROXY_SWAP_DISCRIMINATOR, "proxy_swap", okxNoCommission},
+	{OKX_COMMISSION_SPL_SWAP_DISCRIMINATOR, "commission_spl_swap", okxCommissionRate},
+	{OKX_COMMISSION_SOL_SWAP_DISCRIMINATOR, "commission_sol_swap", okxCommissionRate},
+	{OKX_COMMISSION_SPL_PROXY_SWAP_DISCRIMINATOR, "commission_spl_proxy_swap", okxCommissionRate},
+	{OKX_COMMISSION_SOL_PROXY_SWAP_DISCRIMINATOR, "commission_sol_proxy_swap", okxCommissionRate},
+	{OKX_COMMISSION_SPL_SWAP2_DISCRIMINATOR, "commission_spl_swap2", okxCommissionInfo},
+	{OKX_COMMISSION_SOL_SWAP2_DISCRIMINATOR, "commission_sol_swap2", okxCommissionInfo},
+	{OKX_PLATFORM_FEE_SPL_PROXY_SWAP_V2, "platform_fee_spl_proxy_swap_v2", okxPlatformFee},
+	{OKX_PLATFORM_FEE_SOL_PROXY_SWAP_V2, "platform_fee_sol_proxy_swap_v2", okxPlatformFee},
+}
+
+// okxDexNames is the Dex enum of the OKX router route args
+var okxDexNames = []string{
+	"SplTokenSwap", "StableSwap", "Whirlpool", "MeteoraDynamicpool", "RaydiumSwap", "RaydiumStableSwap",
+	"RaydiumClmmSwap", "AldrinExchangeV1", "AldrinExchangeV2", "LifinityV1", "LifinityV2", "RaydiumClmmSwapV2",
+	"FluxBeam", "MeteoraDlmm", "RaydiumCpmmSwap", "OpenBookV2", "WhirlpoolV2", "Phoenix", "ObricV2",
+	"SanctumAddLiq", "SanctumRemoveLiq", "SanctumNonWsolSwap", "SanctumWsolSwap", "PumpfunBuy", "PumpfunSell",
+	"StabbleSwap", "SanctumRouter", "MeteoraVaultDeposit", "MeteoraVaultWithdraw", "Saros", "MeteoraLst",
+	"Solfi", "QualiaSwap", "Zerofi", "PumpfunammBuy", "PumpfunammSell", "Virtuals", "VertigoBuy", "VertigoSell",
+	"PerpetualsAddLiq", "PerpetualsRemoveLiq", "PerpetualsSwap", "RaydiumLaunchpad", "LetsBonkFun", "Woofi",
+	"MeteoraDbc", "MeteoraDlmmSwap2", "MeteoraDAMMV2",
+}
+
+type OKXDex uint8
+
+func (d OKXDex) String() string {
+	if int(d) < len(okxDexNames) {
+		return okxDexNames[d]
 	}
+	return fmt.Sprintf("Dex(%d)", d)
+}
 
-	if len(parentInstruction.Data) < 8 {
-		return nil
+type OKXRouteStep struct {
+	Dexes   []OKXDex
+	Weights []uint8
+}
+
+type OKXSwapArgs struct {
+	AmountIn        uint64
+	ExpectAmountOut uint64
+	MinReturn       uint64
+	Amounts         []uint64
+	Routes          [][]OKXRouteStep
+}
+
+// OKXHop is one dex of the decoded route, Weight is its share of the hop in percent
+type OKXHop struct {
+	Hop    int
+	Dex    string
+	Weight uint8
+}
+
+// OKXSwap is the decoded OKX router instruction, the hops executed are reported as legs
+type OKXSwap struct {
+	Instruction         string
+	ProgramID           solana.PublicKey
+	Payer               solana.PublicKey
+	Args                OKXSwapArgs
+	Hops                []OKXHop
+	CommissionRate      uint32
+	CommissionFromInput bool
+	CommissionAccount   solana.PublicKey
+	PlatformFeeRate     uint16 // referrer fee rate of the platform_fee instructions
+	PlatformFeeAccount  solana.PublicKey
+	OrderID             uint64
+	Fees                []SwapFee
+}
+
+var okxRouterPrograms = []solana.PublicKey{
+	OKX_DEX_ROUTER_PROGRAM_ID,
+	OKX_DEX_ROUTER_V1_PROGRAM_ID,
+	OKX_DEX_PROXY_PROGRAM_ID,
+}
+
+func isOKXRouterProgram(programID solana.PublicKey) bool {
+	for _, id := range okxRouterPrograms {
+		if id.Equals(programID) {
+			return true
+		}
 	}
+	return false
+}
 
-	decodedBytes, err := base58.Decode(parentInstruction.Data.String())
-	if err != nil {
-		return nil
+// innerContainsOKXRouter checks whether an outer instruction calls one of the OKX router programs through CPI
+func (p *Parser) innerContainsOKXRouter(instructionIndex int) bool {
+	for _, inner := range p.getInnerInstructions(instructionIndex) {
+		if isOKXRouterProgram(p.allAccountKeys[inner.ProgramIDIndex]) {
+			return true
+		}
 	}
+	return false
+}
 
-	discriminator := decodedBytes[:8]
+// processOKXSwaps processes an OKX route, the outer instruction is either the router or a program calling it through CPI
+func (p *Parser) processOKXSwaps(instructionIndex int) []SwapData {
+	instructions := []solana.CompiledInstruction{p.txInfo.Message.Instructions[instructionIndex]}
+	for _, inner := range p.getInnerInstructions(instructionIndex) {
+		instructions = append(instructions, p.convertRPCToSolanaInstruction(inner))
+	}
 
-	var swaps []SwapData
+	var route *OKXSwap
+	for _, inst := range instructions {
+		if route = p.parseOKXSwapInstruction(inst); route != nil {
+			break
+		}
+	}
+	if route == nil {
+		return nil
+	}
 
-	switch {
-	case bytes.Equal(discriminator, OKX_SWAP_DISCRIMINATOR[:]):
-		return p.processOKXRouterSwaps(instructionIndex)
+	legs := p.processOKXRouterSwaps(instructionIndex, route)
+	if len(legs) == 0 {
+		// unknown venues, fall back on the transfers of the route
+		return p.processRaydSwaps(instructionIndex)
+	}
 
-	case bytes.Equal(discriminator, OKX_SWAP2_DISCRIMINATOR[:]):
-		swaps = append(swaps, p.processOKXRouterSwaps(instructionIndex)...)
+	return append([]SwapData{{Type: OKX, Data: route}}, legs...)
+}
 
-	case bytes.Equal(discriminator, OKX_COMMISSION_SPL_SWAP2_DISCRIMINATOR[:]):
-		swaps = append(swaps, p.processOKXRouterSwaps(instructionIndex)...)
+func (p *Parser) parseOKXSwapInstruction(inst solana.CompiledInstruction) *OKXSwap {
+	programID := p.allAccountKeys[inst.ProgramIDIndex]
+	if !isOKXRouterProgram(programID) || len(inst.Data) < 8 || len(inst.Accounts) == 0 {
+		return nil
+	}
 
-	default:
+	decodedBytes, err := base58.Decode(inst.Data.String())
+	if err != nil {
+		p.Log.Errorf("error decoding OKX instruction data: %s", err)
 		return nil
 	}
 
-	return swaps
+	for _, instruction := range okxInstructions {
+		if !bytes.Equal(decodedBytes[:8], instruction.discriminator[:]) {
+			continue
+		}
+
+		swap := &OKXSwap{
+			Instruction: instruction.name,
+			ProgramID:   programID,
+			Payer:       p.allAccountKeys[inst.Accounts[0]],
+		}
+		decoder := ag_binary.NewBorshDecoder(decodedBytes[8:])
+		if err := decoder.Decode(&swap.Args); err != nil {
+			p.Log.Errorf("error unmarshaling OKX SwapArgs: %s", err)
+			return nil
+		}
+		for hop, steps := range swap.Args.Routes {
+			for _, step := range steps {
+				for k, dex := range step.Dexes {
+					var weight uint8
+					if k < len(step.Weights) {
+						weight = step.Weights[k]
+					}
+					swap.Hops = append(swap.Hops, OKXHop{Hop: hop, Dex: dex.String(), Weight: weight})
+				}
+			}
+		}
+
+		switch instruction.commission {
+		case okxCommissionRate:
+			rate, err := decoder.ReadUint16(ag_binary.LE)
+			if err != nil {
+				break
+			}
+			fromInput, err := decoder.ReadBool()
+			if err != nil {
+				break
+			}
+			swap.CommissionRate, swap.CommissionFromInput = uint32(rate), fromInput
+		case okxCommissionInfo, okxPlatformFee:
+			info, err := decoder.ReadUint32(ag_binary.LE)
+			if err != nil {
+				break
+			}
+			swap.CommissionRate, swap.CommissionFromInput = info&0x7fffffff, info>>31 == 1
+			if instruction.commission != okxPlatformFee {
+				break
+			}
+			if swap.PlatformFeeRate, err = decoder.ReadUint16(ag_binary.LE); err != nil {
+				break
+			}
+			swap.OrderID, _ = decoder.ReadUint64(ag_binary.LE)
+		}
+		// payer, source token account, destination token account, source mint, destination mint, commission account,
+		// platform fee account, ...
+		if instruction.commission != okxNoCommission && len(inst.Accounts) > 5 {
+			swap.CommissionAccount = p.allAccountKeys[inst.Accounts[5]]
+		}
+		if instruction.commission == okxPlatformFee && len(inst.Accounts) > 6 {
+			swap.PlatformFeeAccount = p.allAccountKeys[inst.Accounts[6]]
+		}
+		return swap
+	}
+	return nil
 }
 
-func (p *Parser) processOKXRouterSwaps(instructionIndex int) []SwapData {
+// processOKXRouterSwaps builds a leg for every venue instruction of the route. The venue handlers
+// read the CPIs of the venue instruction when there is one, other venues are matched on the transfers
+// that follow the instruction.
+func (p *Parser) processOKXRouterSwaps(instructionIndex int, route *OKXSwap) []SwapData {
 	var swaps []SwapData
-	seen := make(map[string]bool)
-	processedProtocols := make(map[SwapType]bool)
 
 	innerInstructions := p.getInnerInstructions(instructionIndex)
 	if len(innerInstructions) == 0 {
 		return swaps
 	}
 
-	for _, inner := range innerInstructions {
-		progID := p.allAccountKeys[inner.ProgramIDIndex]
+	// owners of the route accounts, the hops move tokens between accounts owned by these
+	userSide := map[string]bool{route.Payer.String(): true}
 
-		switch {
-		case progID.Equals(RAYDIUM_V4_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_CPMM_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_AMM_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID):
-			if processedProtocols[RAYDIUM] {
-				continue
-			}
-			if raydSwaps := p.processRaydSwaps(instructionIndex); len(raydSwaps) > 0 {
-				for _, swap := range raydSwaps {
-					key := getSwapKey(swap)
-					if !seen[key] {
-						p.Log.Debugf("adding raydium swap: %s", key)
-						swaps = append(swaps, swap)
-						seen[key] = true
-					}
-				}
-				processedProtocols[RAYDIUM] = true
+	ends := p.cpiEnds(instructionIndex)
+	for j, inner := range innerInstructions {
+		inst := p.convertRPCToSolanaInstruction(inner)
+		progID := p.allAccountKeys[inst.ProgramIDIndex]
+		following, subtree := innerInstructions[j+1:], innerInstructions[j+1:ends[j]]
+
+		if transfer := p.parseRouteMovement(inst); transfer != nil {
+			if fee := p.okxRouteFee(route, transfer); fee != nil {
+				route.Fees = append(route.Fees, *fee)
 			}
+			continue
+		}
 
-		case progID.Equals(ORCA_PROGRAM_ID):
-			if processedProtocols[ORCA] {
-				continue
+		if ammName(progID) == UNKNOWN || p.isEventInstruction(inst) {
+			continue
+		}
+
+		var swap *SwapData
+		switch {
+		case isPropAmmProgram(progID):
+			if s := p.parsePropAmmSwap(inst, subtree); s != nil {
+				swap = &SwapData{Type: s.AMM, Data: s}
 			}
-			if orcaSwaps := p.processOrcaSwaps(instructionIndex); len(orcaSwaps) > 0 {
-				for _, swap := range orcaSwaps {
-					key := getSwapKey(swap)
-					if !seen[key] {
-						p.Log.Debugf("adding orca swap: %s", key)
-						swaps = append(swaps, swap)
-						seen[key] = true
-					}
-				}
-				processedProtocols[ORCA] = true
+		case isStableSwapProgram(progID):
+			if s := p.parseStableSwap(inst, subtree); s != nil {
+				swap = &SwapData{Type: s.AMM, Data: s}
 			}
-
-		case progID.Equals(METEORA_PROGRAM_ID) ||
-			progID.Equals(METEORA_POOLS_PROGRAM_ID):
-			if processedProtocols[METEORA] {
-				continue
+		case isStakePoolProgram(progID):
+			if s := p.parseStakePoolSwap(inst, subtree); s != nil {
+				swap = &SwapData{Type: SANCTUM, Data: s}
 			}
-			if meteoraSwaps := p.processMeteoraSwaps(instructionIndex); len(meteoraSwaps) > 0 {
-				for _, swap := range meteoraSwaps {
-					key := getSwapKey(swap)
-					if !seen[key] {
-						p.Log.Debugf("adding meteora swap: %s", key)
-						swaps = append(swaps, swap)
-						seen[key] = true
-					}
-				}
-				processedProtocols[METEORA] = true
+		case progID.Equals(SANCTUM_INFINITY_PROGRAM_ID):
+			if s := p.parseInfinitySwap(inst, subtree); s != nil {
+				swap = &SwapData{Type: SANCTUM_INFINITY, Data: s}
 			}
-
-		case progID.Equals(PUMP_FUN_PROGRAM_ID):
-			if processedProtocols[PUMP_FUN] {
-				continue
+		case p.isPhoenixSwapInstruction(inst):
+			if s := p.parsePhoenixSwap(inst, subtree); s != nil {
+				swap = &SwapData{Type: PHOENIX, Data: s}
 			}
-			if pumpfunSwaps := p.processPumpfunSwaps(instructionIndex); len(pumpfunSwaps) > 0 {
-				for _, swap := range pumpfunSwaps {
-					key := getSwapKey(swap)
-					if !seen[key] {
-						p.Log.Debugf("adding pumpfun swap: %s", key)
-						swaps = append(swaps, swap)
-						seen[key] = true
-					}
-				}
-				processedProtocols[PUMP_FUN] = true
+		case p.isOpenbookOrderInstruction(inst):
+			if s := p.parseOpenbookOrder(inst, p.invocationOrdinal(OPENBOOK_V2_PROGRAM_ID, instructionIndex, j)); s != nil {
+				swap = &SwapData{Type: OPENBOOK, Data: s}
 			}
-
 		default:
-			p.Log.Debugf("instruction %d: skipping unknown inner instruction", instructionIndex)
+			if s := p.parseVenueTransfers(inst, following, userSide, route); s != nil {
+				swap = &SwapData{Type: s.AMM, Data: s}
+			}
 		}
+		if swap == nil {
+			continue
+		}
+		leg := swap.Data.(legData).swapLeg()
+		p.Log.Debugf("adding OKX leg %s %s -> %s", leg.AMM, leg.TokenInMint, leg.TokenOutMint)
+		swaps = append(swaps, *swap)
 	}
 
 	return swaps
 }
 
-// getSwapKey generates a unique key for a swap based on its type and amounts
-func getSwapKey(swap SwapData) string {
-	switch data := swap.Data.(type) {
-	case *TransferCheck:
-		return fmt.Sprintf("%s-%s-%s", swap.Type, data.Info.TokenAmount.Amount, data.Info.Mint)
-	case *TransferData:
-		return fmt.Sprintf("%s-%d-%s", swap.Type, data.Info.Amount, data.Mint)
-	default:
-		return fmt.Sprintf("%s-%v", swap.Type, data)
+// VenueSwap is a hop through a venue without a dedicated handler, rebuilt from its transfers
+type VenueSwap struct {
+	AMM              SwapType
+	ProgramID        solana.PublicKey
+	TokenInMint      solana.PublicKey
+	TokenInAmount    uint64
+	TokenInDecimals  uint8
+	TokenOutMint     solana.PublicKey
+	TokenOutAmount   uint64
+	TokenOutDecimals uint8
+}
+
+// parseVenueTransfers matches the transfer paid by the route and the one received back, up to the next venue instruction
+func (p *Parser) parseVenueTransfers(inst solana.CompiledInstruction, following []rpc.CompiledInstruction, userSide map[string]bool, route *OKXSwap) *VenueSwap {
+	progID := p.allAccountKeys[inst.ProgramIDIndex]
+	swap := &VenueSwap{AMM: ammName(progID), ProgramID: progID}
+
+	var in, out *tokenMovement
+	for _, next := range following {
+		nextInst := p.convertRPCToSolanaInstruction(next)
+		nextProgID := p.allAccountKeys[nextInst.ProgramIDIndex]
+		if ammName(nextProgID) != UNKNOWN && !p.isEventInstruction(nextInst) {
+			break
+		}
+		transfer := p.parseRouteMovement(nextInst)
+		if transfer == nil || p.okxRouteFee(route, transfer) != nil {
+			continue
+		}
+		switch {
+		case in == nil && userSide[transfer.user]:
+			in = transfer
+		case out == nil && !userSide[transfer.user] && (in == nil || transfer.mint != in.mint):
+			out = transfer
+		}
+		if in != nil && out != nil {
+			break
+		}
+	}
+	if in == nil || out == nil {
+		return nil
+	}
+
+	// the next hop spends from the account that received this one
+	if owner := p.tokenAccountOwner(solana.MustPublicKeyFromBase58(out.destination)); !owner.IsZero() {
+		userSide[owner.String()] = true
+	} else {
+		userSide[out.destination] = true
 	}
+
+	swap.TokenInMint = solana.MustPublicKeyFromBase58(in.mint)
+	swap.TokenInAmount = in.amount
+	swap.TokenInDecimals = in.decimals
+	swap.TokenOutMint = solana.MustPublicKeyFromBase58(out.mint)
+	swap.TokenOutAmount = out.amount
+	swap.TokenOutDecimals = out.decimals
+	return swap
+}
+
+// parseRouteMovement is parseTokenMovement extended to lamport transfers, which are reported in the native mint
+func (p *Parser) parseRouteMovement(inst solana.CompiledInstruction) *tokenMovement {
+	if transfer := p.parseTokenMovement(inst); transfer != nil {
+		return transfer
+	}
+	if !p.isSystemTransfer(inst) {
+		return nil
+	}
+	transfer := p.processSystemTransfer(inst)
+	if transfer == nil {
+		return nil
+	}
+	return &tokenMovement{
+		user:        transfer.From,
+		source:      transfer.From,
+		destination: transfer.To,
+		mint:        NATIVE_SOL_MINT_PROGRAM_ID.String(),
+		amount:      transfer.Amount,
+		decimals:    9,
+	}
+}
+
+// okxRouteFee returns the commission or the platform fee a route transfer pays, nil for other transfers
+func (p *Parser) okxRouteFee(route *OKXSwap, transfer *tokenMovement) *SwapFee {
+	for _, fee := range []struct {
+		feeType string
+		account solana.PublicKey
+	}{
+		{"commission", route.CommissionAccount},
+		{"platform", route.PlatformFeeAccount},
+	} {
+		if fee.account.IsZero() || !p.isCommissionTransfer(transfer, fee.account) {
+			continue
+		}
+		return &SwapFee{
+			Type:      fee.feeType,
+			Mint:      solana.MustPublicKeyFromBase58(transfer.mint),
+			Amount:    transfer.amount,
+			Recipient: fee.account,
+		}
+	}
+	return nil
+}
+
+// isCommissionTransfer checks whether a transfer pays the commission account or a token account it owns
+func (p *Parser) isCommissionTransfer(transfer *tokenMovement, commissionAccount solana.PublicKey) bool {
+	if transfer.destination == commissionAccount.String() {
+		return true
+	}
+	return p.tokenAccountOwner(solana.MustPublicKeyFromBase58(transfer.destination)).Equals(commissionAccount)
+}
+
+// isEventInstruction checks whether the instruction is an anchor self-CPI event
+func (p *Parser) isEventInstruction(inst solana.CompiledInstruction) bool {
+	if len(inst.Data) < 16 {
+		return false
+	}
+	decodedBytes, err := base58.Decode(inst.Data.String())
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(decodedBytes[:8], eventIxTag[:])
+}
+
+func (s *VenueSwap) swapLeg() SwapLeg {
+	return SwapLeg{
+		AMM:              string(s.AMM),
+		ProgramID:        s.ProgramID,
+		TokenInMint:      s.TokenInMint,
+		TokenInAmount:    s.TokenInAmount,
+		TokenInDecimals:  s.TokenInDecimals,
+		TokenOutMint:     s.TokenOutMint,
+		TokenOutAmount:   s.TokenOutAmount,
+		TokenOutDecimals: s.TokenOutDecimals,
+		Data:             s,
+	}
+}
+
+func (s *VenueSwap) swapFees() []SwapFee {
+	return nil
+}
+
+func (s *VenueSwap) timestamp() int64 {
+	return 0
 }
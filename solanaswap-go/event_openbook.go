@@ -0,0 +1,287 @@
+package solanaswapgo
+
+import (
+	"bytes"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/mr-tron/base58"
+)
+
+var (
+	OpenbookPlaceTakeOrderDiscriminator      = [8]byte{3, 44, 71, 3, 26, 199, 203, 85}
+	OpenbookPlaceOrderDiscriminator          = [8]byte{51, 194, 155, 175, 109, 130, 96, 106}
+	OpenbookFillLogDiscriminator             = [8]byte{150, 23, 41, 148, 152, 162, 215, 64}
+	OpenbookTotalOrderFillEventDiscriminator = [8]byte{8, 235, 48, 58, 174, 76, 156, 105}
+)
+
+type OpenbookSide uint8
+
+const (
+	OpenbookSideBid OpenbookSide = iota
+	OpenbookSideAsk
+)
+
+func (s OpenbookSide) String() string {
+	if s == OpenbookSideBid {
+		return "bid"
+	}
+	return "ask"
+}
+
+type OpenbookPlaceTakeOrderArgs struct {
+	Side                      uint8
+	PriceLots                 int64
+	MaxBaseLots               int64
+	MaxQuoteLotsIncludingFees int64
+	OrderType                 uint8
+	Limit                     uint8
+}
+
+type OpenbookPlaceOrderArgs struct {
+	Side                      uint8
+	PriceLots                 int64
+	MaxBaseLots               int64
+	MaxQuoteLotsIncludingFees int64
+	ClientOrderID             uint64
+	OrderType                 uint8
+	ExpiryTimestamp           uint64
+	SelfTradeBehavior         uint8
+	Limit                     uint8
+}
+
+type OpenbookFillLog struct {
+	Market             solana.PublicKey
+	TakerSide          uint8
+	MakerSlot          uint8
+	MakerOut           bool
+	Timestamp          uint64
+	SeqNum             uint64
+	Maker              solana.PublicKey
+	MakerClientOrderID uint64
+	MakerFee           uint64
+	MakerTimestamp     uint64
+	Taker              solana.PublicKey
+	TakerClientOrderID uint64
+	TakerFeeCeil       uint64
+	Price              int64
+	Quantity           int64
+}
+
+type OpenbookTotalOrderFillEvent struct {
+	Side                  uint8
+	Taker                 solana.PublicKey
+	TotalQuantityPaid     uint64
+	TotalQuantityReceived uint64
+	Fees                  uint64
+}
+
+type OpenbookFill struct {
+	Maker              solana.PublicKey
+	MakerClientOrderID uint64
+	MakerOut           bool
+	SeqNum             uint64
+	PriceLots          int64
+	BaseLots           int64
+	MakerFee           uint64
+	TakerFee           uint64
+}
+
+// OpenbookSwap is the taker side of an OpenBook v2 order, quantities are native amounts
+type OpenbookSwap struct {
+	Instruction   string
+	Market        solana.PublicKey
+	Taker         solana.PublicKey
+	Side          OpenbookSide
+	BaseMint      solana.PublicKey
+	QuoteMint     solana.PublicKey
+	BaseAmount    uint64
+	QuoteAmount   uint64
+	TakerFees     uint64
+	MakerFees     uint64
+	BaseDecimals  uint8
+	QuoteDecimals uint8
+	Makers        []solana.PublicKey
+	Fills         []OpenbookFill
+	Timestamp     int64
+}
+
+func (p *Parser) processOpenbookSwaps(instructionIndex int) []SwapData {
+	var swaps []SwapData
+
+	outerInstruction := p.txInfo.Message.Instructions[instructionIndex]
+	if p.isOpenbookOrderInstruction(outerInstruction) {
+		if swap := p.parseOpenbookOrder(outerInstruction, p.invocationOrdinal(OPENBOOK_V2_PROGRAM_ID, instructionIndex, -1)); swap != nil {
+			swaps = append(swaps, SwapData{Type: OPENBOOK, Data: swap})
+		}
+	}
+
+	for j, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		inst := p.convertRPCToSolanaInstruction(innerInstruction)
+		if !p.isOpenbookOrderInstruction(inst) {
+			continue
+		}
+		if swap := p.parseOpenbookOrder(inst, p.invocationOrdinal(OPENBOOK_V2_PROGRAM_ID, instructionIndex, j)); swap != nil {
+			swaps = append(swaps, SwapData{Type: OPENBOOK, Data: swap})
+		}
+	}
+
+	return swaps
+}
+
+func (p *Parser) isOpenbookOrderInstruction(inst solana.CompiledInstruction) bool {
+	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(OPENBOOK_V2_PROGRAM_ID) || len(inst.Data) < 8 {
+		return false
+	}
+	return (bytes.Equal(inst.Data[:8], OpenbookPlaceTakeOrderDiscriminator[:]) && len(inst.Accounts) >= 11) ||
+		(bytes.Equal(inst.Data[:8], OpenbookPlaceOrderDiscriminator[:]) && len(inst.Accounts) >= 9)
+}
+
+// parseOpenbookOrder decodes an order instruction and the fill logs of its invocation
+func (p *Parser) parseOpenbookOrder(inst solana.CompiledInstruction, invocation int) *OpenbookSwap {
+	decodedBytes, err := base58.Decode(inst.Data.String())
+	if err != nil {
+		p.Log.Errorf("error decoding OpenBook instruction data: %s", err)
+		return nil
+	}
+
+	swap := &OpenbookSwap{}
+	decoder := ag_binary.NewBorshDecoder(decodedBytes[8:])
+	if bytes.Equal(decodedBytes[:8], OpenbookPlaceTakeOrderDiscriminator[:]) {
+		var args OpenbookPlaceTakeOrderArgs
+		if err := decoder.Decode(&args); err != nil {
+			p.Log.Errorf("error unmarshaling OpenBook PlaceTakeOrder: %s", err)
+			return nil
+		}
+		swap.Instruction = "place_take_order"
+		swap.Side = OpenbookSide(args.Side)
+		swap.Taker = p.allAccountKeys[inst.Accounts[0]]
+		swap.Market = p.allAccountKeys[inst.Accounts[2]]
+		swap.BaseMint, swap.BaseDecimals = p.tokenAccountMint(p.allAccountKeys[inst.Accounts[6]])
+		swap.QuoteMint, swap.QuoteDecimals = p.tokenAccountMint(p.allAccountKeys[inst.Accounts[7]])
+	} else {
+		var args OpenbookPlaceOrderArgs
+		if err := decoder.Decode(&args); err != nil {
+			p.Log.Errorf("error unmarshaling OpenBook PlaceOrder: %s", err)
+			return nil
+		}
+		swap.Instruction = "place_order"
+		swap.Side = OpenbookSide(args.Side)
+		swap.Taker = p.allAccountKeys[inst.Accounts[0]]
+		swap.Market = p.allAccountKeys[inst.Accounts[4]]
+		// place_order only moves the paying side, the received side is settled later
+		vaultMint, vaultDecimals := p.tokenAccountMint(p.allAccountKeys[inst.Accounts[8]])
+		if swap.Side == OpenbookSideBid {
+			swap.QuoteMint, swap.QuoteDecimals = vaultMint, vaultDecimals
+		} else {
+			swap.BaseMint, swap.BaseDecimals = vaultMint, vaultDecimals
+		}
+	}
+
+	invocations := p.programDataByInvocation(OPENBOOK_V2_PROGRAM_ID)
+	if invocation < 0 || invocation >= len(invocations) {
+		return nil
+	}
+
+	var total *OpenbookTotalOrderFillEvent
+	makers := make(map[solana.PublicKey]bool)
+	for _, data := range invocations[invocation] {
+		if len(data) < 8 {
+			continue
+		}
+		switch {
+		case bytes.Equal(data[:8], OpenbookFillLogDiscriminator[:]):
+			var fill OpenbookFillLog
+			if err := ag_binary.NewBorshDecoder(data[8:]).Decode(&fill); err != nil {
+				p.Log.Errorf("error unmarshaling OpenBook FillLog: %s", err)
+				continue
+			}
+			swap.Fills = append(swap.Fills, OpenbookFill{
+				Maker:              fill.Maker,
+				MakerClientOrderID: fill.MakerClientOrderID,
+				MakerOut:           fill.MakerOut,
+				SeqNum:             fill.SeqNum,
+				PriceLots:          fill.Price,
+				BaseLots:           fill.Quantity,
+				MakerFee:           fill.MakerFee,
+				TakerFee:           fill.TakerFeeCeil,
+			})
+			swap.MakerFees += fill.MakerFee
+			swap.Timestamp = int64(fill.Timestamp)
+			if !makers[fill.Maker] {
+				makers[fill.Maker] = true
+				swap.Makers = append(swap.Makers, fill.Maker)
+			}
+		case bytes.Equal(data[:8], OpenbookTotalOrderFillEventDiscriminator[:]):
+			var event OpenbookTotalOrderFillEvent
+			if err := ag_binary.NewBorshDecoder(data[8:]).Decode(&event); err != nil {
+				p.Log.Errorf("error unmarshaling OpenBook TotalOrderFillEvent: %s", err)
+				continue
+			}
+			total = &event
+		}
+	}
+
+	if total == nil {
+		// the order rested on the book without taking liquidity
+		return nil
+	}
+
+	swap.Taker = total.Taker
+	swap.TakerFees = total.Fees
+	if OpenbookSide(total.Side) == OpenbookSideBid {
+		swap.QuoteAmount = total.TotalQuantityPaid
+		swap.BaseAmount = total.TotalQuantityReceived
+	} else {
+		swap.BaseAmount = total.TotalQuantityPaid
+		swap.QuoteAmount = total.TotalQuantityReceived
+	}
+
+	return swap
+}
+
+// tokenAccountMint resolves the mint and decimals of a token account seen in the transaction
+func (p *Parser) tokenAccountMint(account solana.PublicKey) (solana.PublicKey, uint8) {
+	info, ok := p.splTokenInfoMap[account.String()]
+	if !ok || info.Mint == "" {
+		return solana.PublicKey{}, 0
+	}
+	mint, err := solana.PublicKeyFromBase58(info.Mint)
+	if err != nil {
+		return solana.PublicKey{}, 0
+	}
+	return mint, info.Decimals
+}
+
+func (s *OpenbookSwap) swapLeg() SwapLeg {
+	leg := SwapLeg{
+		AMM:       string(OPENBOOK),
+		ProgramID: OPENBOOK_V2_PROGRAM_ID,
+		Pool:      s.Market,
+		Data:      s,
+	}
+	if s.Side == OpenbookSideBid {
+		leg.TokenInMint, leg.TokenInAmount, leg.TokenInDecimals = s.QuoteMint, s.QuoteAmount, s.QuoteDecimals
+		leg.TokenOutMint, leg.TokenOutAmount, leg.TokenOutDecimals = s.BaseMint, s.BaseAmount, s.BaseDecimals
+	} else {
+		leg.TokenInMint, leg.TokenInAmount, leg.TokenInDecimals = s.BaseMint, s.BaseAmount, s.BaseDecimals
+		leg.TokenOutMint, leg.TokenOutAmount, leg.TokenOutDecimals = s.QuoteMint, s.QuoteAmount, s.QuoteDecimals
+	}
+	return leg
+}
+
+func (s *OpenbookSwap) swapFees() []SwapFee {
+	if s.TakerFees == 0 {
+		return nil
+	}
+	return []SwapFee{{
+		Type:      "taker",
+		Mint:      s.QuoteMint,
+		Amount:    s.TakerFees,
+		Recipient: s.Market,
+	}}
+}
+
+func (s *OpenbookSwap) timestamp() int64 {
+	return s.Timestamp
+}
@@ -0,0 +1,393 @@
+package solanaswapgo
+
+import (
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/mr-tron/base58"
+)
+
+const (
+	PHOENIX_SWAP_INSTRUCTION = 0
+	PHOENIX_LOG_INSTRUCTION  = 15
+)
+
+// phoenix market event tags (PhoenixMarketEvent enum)
+const (
+	phoenixEventUninitialized = iota
+	phoenixEventHeader
+	phoenixEventFill
+	phoenixEventPlace
+	phoenixEventReduce
+	phoenixEventEvict
+	phoenixEventFillSummary
+	phoenixEventFee
+	phoenixEventTimeInForce
+	phoenixEventExpiredOrder
+)
+
+type PhoenixSide uint8
+
+const (
+	PhoenixSideBid PhoenixSide = iota
+	PhoenixSideAsk
+)
+
+func (s PhoenixSide) String() string {
+	if s == PhoenixSideBid {
+		return "bid"
+	}
+	return "ask"
+}
+
+type PhoenixAuditLogHeader struct {
+	Instruction    uint8
+	SequenceNumber uint64
+	Timestamp      int64
+	Slot           uint64
+	Market         solana.PublicKey
+	Signer         solana.PublicKey
+	TotalEvents    uint16
+}
+
+type PhoenixFillEvent struct {
+	Index               uint16
+	MakerID             solana.PublicKey
+	OrderSequenceNumber uint64
+	PriceInTicks        uint64
+	BaseLotsFilled      uint64
+	BaseLotsRemaining   uint64
+}
+
+type PhoenixPlaceEvent struct {
+	Index               uint16
+	OrderSequenceNumber uint64
+	ClientOrderID       ag_binary.Uint128
+	PriceInTicks        uint64
+	BaseLotsPlaced      uint64
+}
+
+type PhoenixReduceEvent struct {
+	Index               uint16
+	OrderSequenceNumber uint64
+	PriceInTicks        uint64
+	BaseLotsRemoved     uint64
+	BaseLotsRemaining   uint64
+}
+
+type PhoenixEvictEvent struct {
+	Index               uint16
+	MakerID             solana.PublicKey
+	OrderSequenceNumber uint64
+	PriceInTicks        uint64
+	BaseLotsEvicted     uint64
+}
+
+type PhoenixFillSummaryEvent struct {
+	Index                uint16
+	ClientOrderID        ag_binary.Uint128
+	TotalBaseLotsFilled  uint64
+	TotalQuoteLotsFilled uint64
+	TotalFeeInQuoteLots  uint64
+}
+
+type PhoenixFeeEvent struct {
+	Index                    uint16
+	FeesCollectedInQuoteLots uint64
+}
+
+type PhoenixTimeInForceEvent struct {
+	Index                          uint16
+	OrderSequenceNumber            uint64
+	LastValidSlot                  uint64
+	LastValidUnixTimestampInSecond uint64
+}
+
+type PhoenixExpiredOrderEvent struct {
+	Index               uint16
+	MakerID             solana.PublicKey
+	OrderSequenceNumber uint64
+	PriceInTicks        uint64
+	BaseLotsRemoved     uint64
+}
+
+// PhoenixLog is the decoded content of a Phoenix Log instruction
+type PhoenixLog struct {
+	Header      PhoenixAuditLogHeader
+	Fills       []PhoenixFillEvent
+	FillSummary *PhoenixFillSummaryEvent
+	Fees        []PhoenixFeeEvent
+}
+
+type PhoenixFill struct {
+	Maker               solana.PublicKey
+	OrderSequenceNumber uint64
+	PriceInTicks        uint64
+	BaseLotsFilled      uint64
+	BaseLotsRemaining   uint64
+	BaseAtoms           uint64
+}
+
+// PhoenixSwap is a taker fill against a Phoenix market
+type PhoenixSwap struct {
+	Market          solana.PublicKey
+	Trader          solana.PublicKey
+	Side            PhoenixSide
+	BaseMint        solana.PublicKey
+	QuoteMint       solana.PublicKey
+	BaseVault       solana.PublicKey
+	QuoteVault      solana.PublicKey
+	BaseLotsFilled  uint64
+	QuoteLotsFilled uint64
+	FeeInQuoteLots  uint64
+	BaseLotSize     uint64
+	QuoteLotSize    uint64
+	BaseAtoms       uint64
+	QuoteAtoms      uint64
+	FeeInQuoteAtoms uint64
+	Fills           []PhoenixFill
+	SequenceNumber  uint64
+	Timestamp       int64
+	BaseDecimals    uint8
+	QuoteDecimals   uint8
+}
+
+func (p *Parser) processPhoenixSwaps(instructionIndex int) []SwapData {
+	var swaps []SwapData
+
+	innerInstructions := p.getInnerInstructions(instructionIndex)
+
+	outerInstruction := p.txInfo.Message.Instructions[instructionIndex]
+	if p.isPhoenixSwapInstruction(outerInstruction) {
+		if swap := p.parsePhoenixSwap(outerInstruction, innerInstructions); swap != nil {
+			swaps = append(swaps, SwapData{Type: PHOENIX, Data: swap})
+		}
+	}
+
+	ends := p.cpiEnds(instructionIndex)
+	for j, innerInstruction := range innerInstructions {
+		inst := p.convertRPCToSolanaInstruction(innerInstruction)
+		if !p.isPhoenixSwapInstruction(inst) {
+			continue
+		}
+		if swap := p.parsePhoenixSwap(inst, innerInstructions[j+1:ends[j]]); swap != nil {
+			swaps = append(swaps, SwapData{Type: PHOENIX, Data: swap})
+		}
+	}
+
+	return swaps
+}
+
+func (p *Parser) isPhoenixSwapInstruction(inst solana.CompiledInstruction) bool {
+	return p.allAccountKeys[inst.ProgramIDIndex].Equals(PHOENIX_PROGRAM_ID) &&
+		len(inst.Accounts) >= 9 && len(inst.Data) >= 3 && inst.Data[0] == PHOENIX_SWAP_INSTRUCTION
+}
+
+func (p *Parser) isPhoenixLogInstruction(inst solana.CompiledInstruction) bool {
+	return p.allAccountKeys[inst.ProgramIDIndex].Equals(PHOENIX_PROGRAM_ID) &&
+		len(inst.Data) > 1 && inst.Data[0] == PHOENIX_LOG_INSTRUCTION
+}
+
+// parsePhoenixSwap decodes a Swap instruction, following contains the inner instructions the swap executed
+func (p *Parser) parsePhoenixSwap(inst solana.CompiledInstruction, following []rpc.CompiledInstruction) *PhoenixSwap {
+	swap := &PhoenixSwap{
+		Market:     p.allAccountKeys[inst.Accounts[2]],
+		Trader:     p.allAccountKeys[inst.Accounts[3]],
+		BaseVault:  p.allAccountKeys[inst.Accounts[6]],
+		QuoteVault: p.allAccountKeys[inst.Accounts[7]],
+		// data: instruction tag, order packet tag, side
+		Side: PhoenixSide(inst.Data[2]),
+	}
+	traderBase := p.allAccountKeys[inst.Accounts[4]].String()
+	traderQuote := p.allAccountKeys[inst.Accounts[5]].String()
+
+	baseInfo := p.splTokenInfoMap[swap.BaseVault.String()]
+	quoteInfo := p.splTokenInfoMap[swap.QuoteVault.String()]
+	if baseInfo.Mint != "" {
+		swap.BaseMint = solana.MustPublicKeyFromBase58(baseInfo.Mint)
+	}
+	if quoteInfo.Mint != "" {
+		swap.QuoteMint = solana.MustPublicKeyFromBase58(quoteInfo.Mint)
+	}
+	swap.BaseDecimals = baseInfo.Decimals
+	swap.QuoteDecimals = quoteInfo.Decimals
+
+	var phoenixLog *PhoenixLog
+	baseFound, quoteFound := false, false
+	for _, following := range following {
+		instr := p.convertRPCToSolanaInstruction(following)
+		switch {
+		case p.isPhoenixLogInstruction(instr) && phoenixLog == nil:
+			decoded, err := p.parsePhoenixLogInstruction(instr)
+			if err != nil {
+				p.Log.Errorf("error processing Phoenix log: %s", err)
+				continue
+			}
+			if decoded.Header.Market.Equals(swap.Market) {
+				phoenixLog = decoded
+			}
+		case p.isTokenTransfer(instr):
+			transfer := p.processTokenTransfer(instr)
+			switch {
+			case !baseFound && isTransferBetween(transfer, traderBase, swap.BaseVault.String()):
+				swap.BaseAtoms = transfer.Info.Amount
+				baseFound = true
+			case !quoteFound && isTransferBetween(transfer, traderQuote, swap.QuoteVault.String()):
+				swap.QuoteAtoms = transfer.Info.Amount
+				quoteFound = true
+			}
+		}
+		if phoenixLog != nil && baseFound && quoteFound {
+			break
+		}
+	}
+
+	if phoenixLog == nil {
+		if !baseFound || !quoteFound {
+			return nil
+		}
+		return swap
+	}
+
+	swap.SequenceNumber = phoenixLog.Header.SequenceNumber
+	swap.Timestamp = phoenixLog.Header.Timestamp
+	if phoenixLog.FillSummary != nil {
+		swap.BaseLotsFilled = phoenixLog.FillSummary.TotalBaseLotsFilled
+		swap.QuoteLotsFilled = phoenixLog.FillSummary.TotalQuoteLotsFilled
+		swap.FeeInQuoteLots = phoenixLog.FillSummary.TotalFeeInQuoteLots
+	}
+
+	// lot sizes live in the market account, derive them from the vault transfers instead
+	if swap.BaseLotsFilled > 0 {
+		swap.BaseLotSize = swap.BaseAtoms / swap.BaseLotsFilled
+	}
+	quoteLotsMoved := swap.QuoteLotsFilled + swap.FeeInQuoteLots
+	if swap.Side == PhoenixSideAsk && swap.QuoteLotsFilled >= swap.FeeInQuoteLots {
+		quoteLotsMoved = swap.QuoteLotsFilled - swap.FeeInQuoteLots
+	}
+	if quoteLotsMoved > 0 {
+		swap.QuoteLotSize = swap.QuoteAtoms / quoteLotsMoved
+	}
+	swap.FeeInQuoteAtoms = swap.FeeInQuoteLots * swap.QuoteLotSize
+
+	for _, fill := range phoenixLog.Fills {
+		swap.Fills = append(swap.Fills, PhoenixFill{
+			Maker:               fill.MakerID,
+			OrderSequenceNumber: fill.OrderSequenceNumber,
+			PriceInTicks:        fill.PriceInTicks,
+			BaseLotsFilled:      fill.BaseLotsFilled,
+			BaseLotsRemaining:   fill.BaseLotsRemaining,
+			BaseAtoms:           fill.BaseLotsFilled * swap.BaseLotSize,
+		})
+	}
+
+	return swap
+}
+
+func isTransferBetween(transfer *TransferData, user string, vault string) bool {
+	return (transfer.Info.Source == user && transfer.Info.Destination == vault) ||
+		(transfer.Info.Source == vault && transfer.Info.Destination == user)
+}
+
+func (p *Parser) parsePhoenixLogInstruction(inst solana.CompiledInstruction) (*PhoenixLog, error) {
+	decodedBytes, err := base58.Decode(inst.Data.String())
+	if err != nil {
+		return nil, fmt.Errorf("error decoding instruction data: %s", err)
+	}
+
+	// the header is written either as the Header variant of the event enum or as a bare struct
+	if decodedBytes[1] == phoenixEventHeader {
+		if phoenixLog, err := handlePhoenixLog(ag_binary.NewBorshDecoder(decodedBytes[1:]), true); err == nil {
+			return phoenixLog, nil
+		}
+	}
+	return handlePhoenixLog(ag_binary.NewBorshDecoder(decodedBytes[1:]), false)
+}
+
+func handlePhoenixLog(decoder *ag_binary.Decoder, taggedHeader bool) (*PhoenixLog, error) {
+	var phoenixLog PhoenixLog
+	if taggedHeader {
+		if _, err := decoder.ReadUint8(); err != nil {
+			return nil, err
+		}
+	}
+	if err := decoder.Decode(&phoenixLog.Header); err != nil {
+		return nil, fmt.Errorf("error unmarshaling PhoenixAuditLogHeader: %s", err)
+	}
+
+	for decoder.HasRemaining() {
+		tag, err := decoder.ReadUint8()
+		if err != nil {
+			return nil, err
+		}
+		var event interface{}
+		switch tag {
+		case phoenixEventFill:
+			event = &PhoenixFillEvent{}
+		case phoenixEventPlace:
+			event = &PhoenixPlaceEvent{}
+		case phoenixEventReduce:
+			event = &PhoenixReduceEvent{}
+		case phoenixEventEvict:
+			event = &PhoenixEvictEvent{}
+		case phoenixEventFillSummary:
+			event = &PhoenixFillSummaryEvent{}
+		case phoenixEventFee:
+			event = &PhoenixFeeEvent{}
+		case phoenixEventTimeInForce:
+			event = &PhoenixTimeInForceEvent{}
+		case phoenixEventExpiredOrder:
+			event = &PhoenixExpiredOrderEvent{}
+		default:
+			return nil, fmt.Errorf("unknown phoenix event tag %d", tag)
+		}
+		if err := decoder.Decode(event); err != nil {
+			return nil, fmt.Errorf("error unmarshaling phoenix event %d: %s", tag, err)
+		}
+		switch e := event.(type) {
+		case *PhoenixFillEvent:
+			phoenixLog.Fills = append(phoenixLog.Fills, *e)
+		case *PhoenixFillSummaryEvent:
+			phoenixLog.FillSummary = e
+		case *PhoenixFeeEvent:
+			phoenixLog.Fees = append(phoenixLog.Fees, *e)
+		}
+	}
+
+	return &phoenixLog, nil
+}
+
+func (s *PhoenixSwap) swapLeg() SwapLeg {
+	leg := SwapLeg{
+		AMM:       string(PHOENIX),
+		ProgramID: PHOENIX_PROGRAM_ID,
+		Pool:      s.Market,
+		Data:      s,
+	}
+	if s.Side == PhoenixSideBid {
+		leg.TokenInMint, leg.TokenInAmount, leg.TokenInDecimals = s.QuoteMint, s.QuoteAtoms, s.QuoteDecimals
+		leg.TokenOutMint, leg.TokenOutAmount, leg.TokenOutDecimals = s.BaseMint, s.BaseAtoms, s.BaseDecimals
+	} else {
+		leg.TokenInMint, leg.TokenInAmount, leg.TokenInDecimals = s.BaseMint, s.BaseAtoms, s.BaseDecimals
+		leg.TokenOutMint, leg.TokenOutAmount, leg.TokenOutDecimals = s.QuoteMint, s.QuoteAtoms, s.QuoteDecimals
+	}
+	return leg
+}
+
+func (s *PhoenixSwap) swapFees() []SwapFee {
+	if s.FeeInQuoteAtoms == 0 {
+		return nil
+	}
+	return []SwapFee{{
+		Type:      "taker",
+		Mint:      s.QuoteMint,
+		Amount:    s.FeeInQuoteAtoms,
+		Recipient: s.Market,
+	}}
+}
+
+func (s *PhoenixSwap) timestamp() int64 {
+	return s.Timestamp
+}
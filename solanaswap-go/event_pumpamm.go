@@ -1,10 +1,12 @@
 package solanaswapgo
 
+//go:generate go run ../cmd/idlgen -idl idls/pump_amm.json -pkg solanaswapgo -prefix PumpAmm -out pumpamm_idl_gen.go
+
 import (
+	"bytes"
 	"fmt"
 	"strconv"
 
-	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
 	"github.com/mr-tron/base58"
 )
@@ -32,7 +34,27 @@ type PumpAmmPool struct {
 	PoolQuoteTokenReserves           uint64
 }
 
+var (
+	PumpAmmBuyEventDiscriminator  = [8]byte{103, 244, 82, 31, 44, 245, 119, 119}
+	PumpAmmSellEventDiscriminator = [8]byte{62, 47, 55, 10, 165, 3, 220, 42}
+)
+
+// PumpAmmEvent is a decoded PumpAmm event, exactly one of Buy and Sell is set
 type PumpAmmEvent struct {
+	Buy  *PumpAmmBuyEvent
+	Sell *PumpAmmSellEvent
+}
+
+// PoolReserves returns the pool reserves after the trade
+func (e *PumpAmmEvent) PoolReserves() (base uint64, quote uint64) {
+	if e.Buy != nil {
+		return e.Buy.PoolBaseTokenReserves, e.Buy.PoolQuoteTokenReserves
+	}
+	return e.Sell.PoolBaseTokenReserves, e.Sell.PoolQuoteTokenReserves
+}
+
+// PumpAmmBuyEvent is the PumpAmm BuyEvent, fields the program added later are zero on older events
+type PumpAmmBuyEvent struct {
 	Timestamp                        int64            `json:"timestamp,string"`
 	BaseAmountOut                    uint64           `json:"baseAmountOut,string"`
 	MaxQuoteAmountIn                 uint64           `json:"maxQuoteAmountIn,string"`
@@ -53,8 +75,58 @@ type PumpAmmEvent struct {
 	UserQuoteTokenAccount            solana.PublicKey `json:"userQuoteTokenAccount"`
 	ProtocolFeeRecipient             solana.PublicKey `json:"protocolFeeRecipient"`
 	ProtocolFeeRecipientTokenAccount solana.PublicKey `json:"protocolFeeRecipientTokenAccount"`
+	// since v2
+	CoinCreator               solana.PublicKey `json:"coinCreator"`
+	CoinCreatorFeeBasisPoints uint64           `json:"coinCreatorFeeBasisPoints,string"`
+	CoinCreatorFee            uint64           `json:"coinCreatorFee,string"`
+	// since v3
+	TrackVolume          bool   `json:"trackVolume"`
+	TotalUnclaimedTokens uint64 `json:"totalUnclaimedTokens,string"`
+	TotalClaimedTokens   uint64 `json:"totalClaimedTokens,string"`
+	CurrentSolVolume     uint64 `json:"currentSolVolume,string"`
+	LastUpdateTimestamp  int64  `json:"lastUpdateTimestamp,string"`
+
+	Version int    `json:"version" bin:"-"`
+	Extra   []byte `json:"extra,omitempty" bin:"-"` // trailing bytes of versions newer than v3
 }
 
+// PumpAmmSellEvent is the PumpAmm SellEvent, fields the program added later are zero on older events
+type PumpAmmSellEvent struct {
+	Timestamp                        int64            `json:"timestamp,string"`
+	BaseAmountIn                     uint64           `json:"baseAmountIn,string"`
+	MinQuoteAmountOut                uint64           `json:"minQuoteAmountOut,string"`
+	UserBaseTokenReserves            uint64           `json:"userBaseTokenReserves,string"`
+	UserQuoteTokenReserves           uint64           `json:"userQuoteTokenReserves,string"`
+	PoolBaseTokenReserves            uint64           `json:"poolBaseTokenReserves,string"`
+	PoolQuoteTokenReserves           uint64           `json:"poolQuoteTokenReserves,string"`
+	QuoteAmountOut                   uint64           `json:"quoteAmountOut,string"`
+	LpFeeBasisPoints                 uint64           `json:"lpFeeBasisPoints,string"`
+	LpFee                            uint64           `json:"lpFee,string"`
+	ProtocolFeeBasisPoints           uint64           `json:"protocolFeeBasisPoints,string"`
+	ProtocolFee                      uint64           `json:"protocolFee,string"`
+	QuoteAmountOutWithoutLpFee       uint64           `json:"quoteAmountOutWithoutLpFee,string"`
+	UserQuoteAmountOut               uint64           `json:"userQuoteAmountOut,string"`
+	Pool                             solana.PublicKey `json:"pool"`
+	User                             solana.PublicKey `json:"user"`
+	UserBaseTokenAccount             solana.PublicKey `json:"userBaseTokenAccount"`
+	UserQuoteTokenAccount            solana.PublicKey `json:"userQuoteTokenAccount"`
+	ProtocolFeeRecipient             solana.PublicKey `json:"protocolFeeRecipient"`
+	ProtocolFeeRecipientTokenAccount solana.PublicKey `json:"protocolFeeRecipientTokenAccount"`
+	// since v2
+	CoinCreator               solana.PublicKey `json:"coinCreator"`
+	CoinCreatorFeeBasisPoints uint64           `json:"coinCreatorFeeBasisPoints,string"`
+	CoinCreatorFee            uint64           `json:"coinCreatorFee,string"`
+
+	Version int    `json:"version" bin:"-"`
+	Extra   []byte `json:"extra,omitempty" bin:"-"` // trailing bytes of versions newer than v2
+}
+
+// field counts of the known PumpAmm event versions
+var (
+	pumpAmmBuyEventVersions  = []int{20, 23, 28}
+	pumpAmmSellEventVersions = []int{20, 23}
+)
+
 func (p *Parser) processPumpAmmSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
 
@@ -89,6 +161,7 @@ func (p *Parser) processTransferCheckInstruction(userAccount string, inner solan
 	amountStr := transfer.Info.TokenAmount.Amount
 	amount, _ := strconv.ParseUint(amountStr, 10, 64)
 	decimals := transfer.Info.TokenAmount.Decimals
+	info := TransferInfo{Amount: amount, Authority: authority, Source: transfer.Info.Source, Destination: transfer.Info.Destination}
 
 	if authority != userAccount {
 		// Output: DEX -> user
@@ -97,7 +170,7 @@ func (p *Parser) processTransferCheckInstruction(userAccount string, inner solan
 			Data: &OutputTransfer{
 				TransferData: TransferData{
 					Mint:     transfer.Info.Mint,
-					Info:     TransferInfo{Amount: amount},
+					Info:     info,
 					Decimals: decimals,
 				},
 			},
@@ -109,7 +182,7 @@ func (p *Parser) processTransferCheckInstruction(userAccount string, inner solan
 			Data: &InputTransfer{
 				TransferData: TransferData{
 					Mint:     transfer.Info.Mint,
-					Info:     TransferInfo{Amount: amount},
+					Info:     info,
 					Decimals: decimals,
 				},
 			},
@@ -127,7 +200,6 @@ func (p *Parser) processTokenTransferInstruction(userAccount string, inner solan
 	}
 	source := transfer.Info.Source
 	authority := transfer.Info.Authority
-	amount := transfer.Info.Amount
 	decimals := transfer.Decimals
 
 	if authority != userAccount || source != userAccount {
@@ -137,7 +209,7 @@ func (p *Parser) processTokenTransferInstruction(userAccount string, inner solan
 			Data: &OutputTransfer{
 				TransferData: TransferData{
 					Mint:     transfer.Mint,
-					Info:     TransferInfo{Amount: amount},
+					Info:     transfer.Info,
 					Decimals: decimals,
 				},
 			},
@@ -149,7 +221,7 @@ func (p *Parser) processTokenTransferInstruction(userAccount string, inner solan
 			Data: &InputTransfer{
 				TransferData: TransferData{
 					Mint:     transfer.Mint,
-					Info:     TransferInfo{Amount: amount},
+					Info:     transfer.Info,
 					Decimals: decimals,
 				},
 			},
@@ -169,6 +241,7 @@ func (p *Parser) processSystemTransferInstruction(userAccount string, inner sola
 	amount := transfer.Amount
 	decimals := uint8(9)
 	mint := NATIVE_SOL_MINT_PROGRAM_ID.String()
+	info := TransferInfo{Amount: amount, Authority: from, Source: from, Destination: transfer.To}
 
 	if from != userAccount {
 		// Output: DEX -> user
@@ -177,7 +250,7 @@ func (p *Parser) processSystemTransferInstruction(userAccount string, inner sola
 			Data: &OutputTransfer{
 				TransferData: TransferData{
 					Mint:     mint,
-					Info:     TransferInfo{Amount: amount},
+					Info:     info,
 					Decimals: decimals,
 				},
 			},
@@ -189,7 +262,7 @@ func (p *Parser) processSystemTransferInstruction(userAccount string, inner sola
 			Data: &InputTransfer{
 				TransferData: TransferData{
 					Mint:     mint,
-					Info:     TransferInfo{Amount: amount},
+					Info:     info,
 					Decimals: decimals,
 				},
 			},
@@ -252,24 +325,28 @@ func (p *Parser) getPumpAmmPool() *PumpAmmPool {
 }
 
 func (p *Parser) processPumpAmmAccounts(inner solana.CompiledInstruction) *PumpAmmPool {
-
-	if len(inner.Accounts) <= 19 {
+	// buy and sell take the same accounts, the 19 of the IDL end with the coin creator vault and are all read
+	// here, the volume accumulators and fee config later versions append after them are not needed
+	if len(inner.Data) < 8 || (!bytes.Equal(inner.Data[:8], PumpAmmBuyDiscriminator[:]) && !bytes.Equal(inner.Data[:8], PumpAmmSellDiscriminator[:])) {
+		return nil
+	}
+	layout, ok := NewPumpAmmBuyAccounts(p.instructionAccountKeys(inner))
+	if !ok {
 		return nil
 	}
 
-	var accounts PumpAmmPool
-	accounts.Pool = p.allAccountKeys[inner.Accounts[0]]
-	accounts.GlobalConfig = p.allAccountKeys[inner.Accounts[2]]
-	accounts.BaseMint = p.allAccountKeys[inner.Accounts[3]]
-	accounts.QuoteMint = p.allAccountKeys[inner.Accounts[4]]
-	accounts.PoolBaseTokenAccount = p.allAccountKeys[inner.Accounts[7]]
-	accounts.PoolQuoteTokenAccount = p.allAccountKeys[inner.Accounts[8]]
-	accounts.ProtocolFeeRecipient = p.allAccountKeys[inner.Accounts[9]]
-	accounts.ProtocolFeeRecipientTokenAccount = p.allAccountKeys[inner.Accounts[10]]
-	accounts.CoinCreatorVaultAta = p.allAccountKeys[inner.Accounts[17]]
-	accounts.CoinCreatorVaultAuthority = p.allAccountKeys[inner.Accounts[18]]
-	return &accounts
-
+	return &PumpAmmPool{
+		Pool:                             layout.Pool,
+		GlobalConfig:                     layout.GlobalConfig,
+		BaseMint:                         layout.BaseMint,
+		QuoteMint:                        layout.QuoteMint,
+		PoolBaseTokenAccount:             layout.PoolBaseTokenAccount,
+		PoolQuoteTokenAccount:            layout.PoolQuoteTokenAccount,
+		ProtocolFeeRecipient:             layout.ProtocolFeeRecipient,
+		ProtocolFeeRecipientTokenAccount: layout.ProtocolFeeRecipientTokenAccount,
+		CoinCreatorVaultAta:              layout.CoinCreatorVaultAta,
+		CoinCreatorVaultAuthority:        layout.CoinCreatorVaultAuthority,
+	}
 }
 
 func (p *Parser) getPumpAmmEvent() *PumpAmmEvent { // anchor Self CPI Log
@@ -312,15 +389,42 @@ func parsePumpAmmEventInstruction(instruction solana.CompiledInstruction) (*Pump
 	if err != nil {
 		return nil, fmt.Errorf("error decoding instruction data: %s", err)
 	}
-	decoder := ag_binary.NewBorshDecoder(decodedBytes[16:])
+	if len(decodedBytes) < 16 || !bytes.Equal(decodedBytes[:8], eventIxTag[:]) {
+		return nil, fmt.Errorf("not an event instruction")
+	}
 
-	return handlePumpAmmEvent(decoder)
+	return handlePumpAmmEvent(decodedBytes[8:])
 }
-func handlePumpAmmEvent(decoder *ag_binary.Decoder) (*PumpAmmEvent, error) {
-	var create PumpAmmEvent
-	if err := decoder.Decode(&create); err != nil {
-		return nil, fmt.Errorf("error unmarshaling PumpAmmEvent: %s", err)
+
+// DecodePumpAmmEvent decodes a BuyEvent or a SellEvent of any known version, data starts with the event
+// discriminator, with or without the self-CPI tag
+func DecodePumpAmmEvent(data []byte) (*PumpAmmEvent, error) {
+	data = bytes.TrimPrefix(data, eventIxTag[:])
+	if len(data) < 8 {
+		return nil, fmt.Errorf("unknown PumpAmm event")
 	}
+	return handlePumpAmmEvent(data)
+}
 
-	return &create, nil
+// handlePumpAmmEvent decodes a BuyEvent or a SellEvent, data starts with the event discriminator
+func handlePumpAmmEvent(data []byte) (*PumpAmmEvent, error) {
+	switch {
+	case bytes.Equal(data[:8], PumpAmmBuyEventDiscriminator[:]):
+		var buy PumpAmmBuyEvent
+		version, extra, err := decodeEventVersions(data[8:], &buy, pumpAmmBuyEventVersions)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling PumpAmm BuyEvent: %s", err)
+		}
+		buy.Version, buy.Extra = version, extra
+		return &PumpAmmEvent{Buy: &buy}, nil
+	case bytes.Equal(data[:8], PumpAmmSellEventDiscriminator[:]):
+		var sell PumpAmmSellEvent
+		version, extra, err := decodeEventVersions(data[8:], &sell, pumpAmmSellEventVersions)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling PumpAmm SellEvent: %s", err)
+		}
+		sell.Version, sell.Extra = version, extra
+		return &PumpAmmEvent{Sell: &sell}, nil
+	}
+	return nil, fmt.Errorf("unknown PumpAmm event")
 }
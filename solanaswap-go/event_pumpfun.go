@@ -1,7 +1,9 @@
 package solanaswapgo
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
@@ -10,8 +12,17 @@ import (
 )
 
 var (
-	PumpfunTradeEventDiscriminator  = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 189, 219, 127, 211, 78, 230, 97, 238}
-	PumpfunCreateEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 27, 114, 169, 77, 222, 235, 99, 118}
+	PumpfunTradeEventDiscriminator    = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 189, 219, 127, 211, 78, 230, 97, 238}
+	PumpfunCreateEventDiscriminator   = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 27, 114, 169, 77, 222, 235, 99, 118}
+	PumpfunCompleteEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 95, 114, 97, 156, 212, 46, 152, 8}
+)
+
+// reserves of a new pump.fun bonding curve, in token base units and lamports
+const (
+	PumpfunInitialVirtualTokenReserves uint64 = 1_073_000_000_000_000
+	PumpfunInitialVirtualSolReserves   uint64 = 30_000_000_000
+	PumpfunInitialRealTokenReserves    uint64 = 793_100_000_000_000
+	PumpfunTokenTotalSupply            uint64 = 1_000_000_000_000_000
 )
 
 type PumpFunPool struct {
@@ -21,13 +32,16 @@ type PumpFunPool struct {
 	BondingCurve           solana.PublicKey
 	AssociatedBondingCurve solana.PublicKey
 	CreatorVault           solana.PublicKey
+	Creator                solana.PublicKey // owner of CreatorVault, known from v3 TradeEvents
 	EventAuthority         solana.PublicKey
 	VirtualSolReserves     uint64
 	VirtualTokenReserves   uint64
 	RealSOLReserves        uint64
 	RealTokenReserves      uint64
+	Complete               bool // the trade bought the last tokens of the curve
 }
 
+// PumpfunTradeEvent is the pump.fun TradeEvent, fields the program added later are zero on older events
 type PumpfunTradeEvent struct {
 	Mint                 solana.PublicKey
 	SolAmount            uint64
@@ -37,10 +51,66 @@ type PumpfunTradeEvent struct {
 	Timestamp            int64
 	VirtualSolReserves   uint64
 	VirtualTokenReserves uint64
-	RealSOLReserves      uint64
-	RealTokenReserves    uint64
+	// since v2
+	RealSOLReserves   uint64
+	RealTokenReserves uint64
+	// since v3
+	FeeRecipient          solana.PublicKey
+	FeeBasisPoints        uint64
+	Fee                   uint64
+	Creator               solana.PublicKey
+	CreatorFeeBasisPoints uint64
+	CreatorFee            uint64
+	// since v4
+	TrackVolume          bool
+	TotalUnclaimedTokens uint64
+	TotalClaimedTokens   uint64
+	CurrentSolVolume     uint64
+	LastUpdateTimestamp  int64
+
+	Version  int    `bin:"-"`
+	Extra    []byte `bin:"-"` // trailing bytes of versions newer than v4
+	Complete bool   `bin:"-"` // a CompleteEvent followed, the trade bought the last tokens of the curve
+}
+
+// PumpfunCompleteEvent is emitted when a trade empties the bonding curve, which can then migrate
+type PumpfunCompleteEvent struct {
+	User         solana.PublicKey
+	Mint         solana.PublicKey
+	BondingCurve solana.PublicKey
+	Timestamp    int64
 }
 
+// pumpfunTradeEventVersions are the field counts of the known TradeEvent versions
+var pumpfunTradeEventVersions = []int{8, 10, 16, 21}
+
+// swapFees returns the protocol and creator fees of the trade, in lamports
+func (e *PumpfunTradeEvent) swapFees() []SwapFee {
+	var fees []SwapFee
+	if e.Fee > 0 {
+		fees = append(fees, SwapFee{Type: "protocol", Mint: NATIVE_SOL_MINT_PROGRAM_ID, Amount: e.Fee, Recipient: e.FeeRecipient})
+	}
+	if e.CreatorFee > 0 {
+		fees = append(fees, SwapFee{Type: "creator", Mint: NATIVE_SOL_MINT_PROGRAM_ID, Amount: e.CreatorFee, Recipient: PumpfunCreatorVault(e.Creator)})
+	}
+	return fees
+}
+
+// PumpfunCreatorVault derives the vault collecting the creator fees of a pump.fun token creator
+func PumpfunCreatorVault(creator solana.PublicKey) solana.PublicKey {
+	vault, _, err := solana.FindProgramAddress([][]byte{[]byte("creator-vault"), creator.Bytes()}, PUMP_FUN_PROGRAM_ID)
+	if err != nil {
+		return solana.PublicKey{}
+	}
+	return vault
+}
+
+var (
+	PumpfunCreateDiscriminator   = [8]byte{24, 30, 200, 40, 5, 28, 7, 119}
+	PumpfunCreateV2Discriminator = [8]byte{214, 144, 76, 236, 95, 139, 49, 180}
+)
+
+// PumpfunCreateEvent is the pump.fun CreateEvent, fields the program added later are zero on older events
 type PumpfunCreateEvent struct {
 	Name         string
 	Symbol       string
@@ -48,13 +118,35 @@ type PumpfunCreateEvent struct {
 	Mint         solana.PublicKey
 	BondingCurve solana.PublicKey
 	User         solana.PublicKey
+	// since v2
+	Creator              solana.PublicKey
+	Timestamp            int64
+	VirtualTokenReserves uint64
+	VirtualSolReserves   uint64
+	RealTokenReserves    uint64
+	TokenTotalSupply     uint64
+	// since v3, filled from the instruction on older events
+	TokenProgram solana.PublicKey
+	IsMayhemMode bool
+
+	Version     int                `bin:"-"`
+	Extra       []byte             `bin:"-"` // trailing bytes of versions newer than v3
+	Instruction string             `bin:"-"` // "create", or "create_v2" for Token-2022 mints
+	DevBuy      *PumpfunTradeEvent `bin:"-"` // first buy of the mint by its creator in the same transaction, nil when none
 }
 
+// pumpfunCreateEventVersions are the field counts of the known CreateEvent versions
+var pumpfunCreateEventVersions = []int{6, 12, 14}
+
 func (p *Parser) processPumpfunSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
 	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
 		if innerInstructionSet.Index == uint16(instructionIndex) {
 			for _, innerInstruction := range innerInstructionSet.Instructions {
+				if p.isPumpfunCompleteEventInstruction(innerInstruction) {
+					p.flagPumpfunComplete(swaps, innerInstruction)
+					continue
+				}
 				if p.isPumpFunTradeEventInstruction(innerInstruction) {
 					eventData, err := p.parsePumpfunTradeEventInstruction(p.convertRPCToSolanaInstruction(innerInstruction))
 					if err != nil {
@@ -70,6 +162,74 @@ func (p *Parser) processPumpfunSwaps(instructionIndex int) []SwapData {
 	return swaps
 }
 
+// flagPumpfunComplete marks the last trade of the mint the CompleteEvent instruction was emitted for
+func (p *Parser) flagPumpfunComplete(swaps []SwapData, inst rpc.CompiledInstruction) {
+	complete, err := handlePumpfunCompleteEvent(inst.Data[16:])
+	if err != nil {
+		p.Log.Errorf("error processing Pumpfun complete event: %s", err)
+		return
+	}
+	for j := len(swaps) - 1; j >= 0; j-- {
+		if trade, ok := swaps[j].Data.(*PumpfunTradeEvent); ok && trade.Mint.Equals(complete.Mint) {
+			trade.Complete = true
+			return
+		}
+	}
+}
+
+// hasPumpfunCompleteEvent checks if the transaction completed the bonding curve of mint
+func (p *Parser) hasPumpfunCompleteEvent(mint solana.PublicKey) bool {
+	found := false
+	p.forEachInstruction(func(inst solana.CompiledInstruction) {
+		if found || !p.allAccountKeys[inst.ProgramIDIndex].Equals(PUMP_FUN_PROGRAM_ID) || len(inst.Data) < 16 ||
+			!bytes.Equal(inst.Data[:16], PumpfunCompleteEventDiscriminator[:]) {
+			return
+		}
+		complete, err := handlePumpfunCompleteEvent(inst.Data[16:])
+		found = err == nil && complete.Mint.Equals(mint)
+	})
+	return found
+}
+
+func handlePumpfunCompleteEvent(data []byte) (*PumpfunCompleteEvent, error) {
+	var complete PumpfunCompleteEvent
+	if err := ag_binary.NewBorshDecoder(data).Decode(&complete); err != nil {
+		return nil, fmt.Errorf("error unmarshaling CompleteEvent: %s", err)
+	}
+
+	return &complete, nil
+}
+
+// Progress returns how much of the tokens for sale the bonding curve sold, from 0 to 1
+func (pool *PumpFunPool) Progress() float64 {
+	if pool.RealTokenReserves >= PumpfunInitialRealTokenReserves {
+		return 0
+	}
+	return 1 - float64(pool.RealTokenReserves)/float64(PumpfunInitialRealTokenReserves)
+}
+
+// MarketCapSol returns the market cap of the token at the curve price, in lamports
+func (pool *PumpFunPool) MarketCapSol() uint64 {
+	if pool.VirtualTokenReserves == 0 {
+		return 0
+	}
+	marketCap := new(big.Int).Mul(new(big.Int).SetUint64(pool.VirtualSolReserves), new(big.Int).SetUint64(PumpfunTokenTotalSupply))
+	return marketCap.Div(marketCap, new(big.Int).SetUint64(pool.VirtualTokenReserves)).Uint64()
+}
+
+// SolToComplete returns the lamports, before fees, that buying the tokens left on the curve costs
+func (pool *PumpFunPool) SolToComplete() uint64 {
+	if pool.RealTokenReserves == 0 || pool.RealTokenReserves >= pool.VirtualTokenReserves {
+		return 0
+	}
+	// constant product: the virtual SOL reserves once the remaining tokens are bought, rounded up
+	k := new(big.Int).Mul(new(big.Int).SetUint64(pool.VirtualSolReserves), new(big.Int).SetUint64(pool.VirtualTokenReserves))
+	remaining := new(big.Int).SetUint64(pool.VirtualTokenReserves - pool.RealTokenReserves)
+	solAfter := new(big.Int).Add(k, new(big.Int).Sub(remaining, big.NewInt(1)))
+	solAfter.Div(solAfter, remaining)
+	return solAfter.Uint64() - pool.VirtualSolReserves
+}
+
 func (p *Parser) getPumpFunPool() *PumpFunPool {
 	if p.txMeta == nil || p.txMeta.InnerInstructions == nil {
 		return nil
@@ -166,34 +326,86 @@ func (p *Parser) parsePumpfunTradeEventInstruction(instruction solana.CompiledIn
 	if err != nil {
 		return nil, fmt.Errorf("error decoding instruction data: %s", err)
 	}
-	decoder := ag_binary.NewBorshDecoder(decodedBytes[16:])
+	if len(decodedBytes) < 16 || !bytes.Equal(decodedBytes[:16], PumpfunTradeEventDiscriminator[:]) {
+		return nil, fmt.Errorf("not a TradeEvent")
+	}
 
-	return handlePumpfunTradeEvent(decoder)
+	return handlePumpfunTradeEvent(decodedBytes[16:])
 }
 
-func handlePumpfunTradeEvent(decoder *ag_binary.Decoder) (*PumpfunTradeEvent, error) {
-	var trade PumpfunTradeEvent
-	if err := decoder.Decode(&trade); err != nil {
-		return nil, fmt.Errorf("error unmarshaling TradeEvent: %s", err)
+// DecodePumpfunTradeEvent decodes a TradeEvent of any known version, data starts with the event
+// discriminator, with or without the self-CPI tag
+func DecodePumpfunTradeEvent(data []byte) (*PumpfunTradeEvent, error) {
+	data = bytes.TrimPrefix(data, eventIxTag[:])
+	if len(data) < 8 || !bytes.Equal(data[:8], PumpfunTradeEventDiscriminator[8:]) {
+		return nil, fmt.Errorf("not a pump.fun TradeEvent")
 	}
-
-	return &trade, nil
+	return handlePumpfunTradeEvent(data[8:])
 }
 
-func (p *Parser) parsePumpfunCreateEventInstruction(instruction rpc.CompiledInstruction) (*PumpfunCreateEvent, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
+func handlePumpfunTradeEvent(data []byte) (*PumpfunTradeEvent, error) {
+	var trade PumpfunTradeEvent
+	version, extra, err := decodeEventVersions(data, &trade, pumpfunTradeEventVersions)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding instruction data: %s", err)
+		return nil, fmt.Errorf("error unmarshaling TradeEvent: %s", err)
 	}
-	decoder := ag_binary.NewBorshDecoder(decodedBytes[16:])
+	trade.Version, trade.Extra = version, extra
 
-	return handlePumpfunCreateEvent(decoder)
+	return &trade, nil
 }
-func handlePumpfunCreateEvent(decoder *ag_binary.Decoder) (*PumpfunCreateEvent, error) {
+
+func handlePumpfunCreateEvent(data []byte) (*PumpfunCreateEvent, error) {
 	var create PumpfunCreateEvent
-	if err := decoder.Decode(&create); err != nil {
+	version, extra, err := decodeEventVersions(data, &create, pumpfunCreateEventVersions)
+	if err != nil {
 		return nil, fmt.Errorf("error unmarshaling CreateEvent: %s", err)
 	}
+	create.Version, create.Extra = version, extra
 
 	return &create, nil
 }
+
+// getPumpfunLaunches returns the pump.fun tokens created by the transaction, by an outer instruction or
+// through CPI, each with the create instruction that emitted it and the dev buy that followed
+func (p *Parser) getPumpfunLaunches() []*PumpfunCreateEvent {
+	var launches []*PumpfunCreateEvent
+	instruction := ""
+	p.forEachInstruction(func(inst solana.CompiledInstruction) {
+		if !p.allAccountKeys[inst.ProgramIDIndex].Equals(PUMP_FUN_PROGRAM_ID) || len(inst.Data) < 8 {
+			return
+		}
+		switch {
+		case bytes.Equal(inst.Data[:8], PumpfunCreateDiscriminator[:]):
+			instruction = "create"
+		case bytes.Equal(inst.Data[:8], PumpfunCreateV2Discriminator[:]):
+			instruction = "create_v2"
+		case len(inst.Data) < 16 || !bytes.Equal(inst.Data[:8], eventIxTag[:]):
+		case bytes.Equal(inst.Data[:16], PumpfunCreateEventDiscriminator[:]):
+			create, err := handlePumpfunCreateEvent(inst.Data[16:])
+			if err != nil {
+				p.Log.Errorf("error processing Pumpfun create event: %s", err)
+				return
+			}
+			create.Instruction = instruction
+			if create.TokenProgram.IsZero() {
+				create.TokenProgram = solana.TokenProgramID
+				if instruction == "create_v2" {
+					create.TokenProgram = solana.Token2022ProgramID
+				}
+			}
+			launches = append(launches, create)
+		case bytes.Equal(inst.Data[:16], PumpfunTradeEventDiscriminator[:]):
+			trade, err := handlePumpfunTradeEvent(inst.Data[16:])
+			if err != nil || !trade.IsBuy {
+				return
+			}
+			for _, launch := range launches {
+				// buys of other wallets in the same transaction are bundlers or snipers
+				if launch.DevBuy == nil && launch.Mint.Equals(trade.Mint) && (trade.User.Equals(launch.User) || trade.User.Equals(launch.Creator)) {
+					launch.DevBuy = trade
+				}
+			}
+		}
+	})
+	return launches
+}
@@ -1,6 +1,9 @@
 package solanaswapgo
 
+//go:generate go run ../cmd/idlgen -idl idls/raydium_launchpad.json -pkg solanaswapgo -prefix RaydiumLaunchpad -out raydium_launchpad_idl_gen.go
+
 import (
+	"bytes"
 	"fmt"
 
 	ag_binary "github.com/gagliardetto/binary"
@@ -62,7 +65,7 @@ func (p *Parser) getRaydiumLaunchpadPool() *RaydiumLaunchpadPool {
 	}
 	for _, inner := range p.txInfo.Message.Instructions {
 
-		if p.allAccountKeys[inner.ProgramIDIndex].Equals(RAYDIUM_Launchpad_PROGRAM_ID) && len(inner.Accounts) >= 18 {
+		if p.allAccountKeys[inner.ProgramIDIndex].Equals(RAYDIUM_Launchpad_PROGRAM_ID) && len(inner.Accounts) >= RaydiumLaunchpadBuyExactInAccountsLen {
 			pumpAmmPool := p.processRaydiumLaunchpadAccounts(inner)
 			if pumpAmmPool != nil {
 				return pumpAmmPool
@@ -73,7 +76,7 @@ func (p *Parser) getRaydiumLaunchpadPool() *RaydiumLaunchpadPool {
 
 	for _, inner := range p.txMeta.InnerInstructions {
 		for _, inst := range inner.Instructions {
-			if p.allAccountKeys[inst.ProgramIDIndex].Equals(RAYDIUM_Launchpad_PROGRAM_ID) && len(inst.Accounts) >= 18 {
+			if p.allAccountKeys[inst.ProgramIDIndex].Equals(RAYDIUM_Launchpad_PROGRAM_ID) && len(inst.Accounts) >= RaydiumLaunchpadBuyExactInAccountsLen {
 				pumpAmmPool := p.processRaydiumLaunchpadAccounts(p.convertRPCToSolanaInstruction(inst))
 				if pumpAmmPool != nil {
 					return pumpAmmPool
@@ -85,19 +88,34 @@ func (p *Parser) getRaydiumLaunchpadPool() *RaydiumLaunchpadPool {
 }
 
 func (p *Parser) processRaydiumLaunchpadAccounts(inner solana.CompiledInstruction) *RaydiumLaunchpadPool {
-	var accounts RaydiumLaunchpadPool
-	accounts.Authority = p.allAccountKeys[inner.Accounts[1]]
-	accounts.GlobalConfig = p.allAccountKeys[inner.Accounts[2]]
-	accounts.PlatformConfig = p.allAccountKeys[inner.Accounts[3]]
-	accounts.PoolState = p.allAccountKeys[inner.Accounts[4]]
-	accounts.BaseVault = p.allAccountKeys[inner.Accounts[7]]
-	accounts.QuoteVault = p.allAccountKeys[inner.Accounts[8]]
-	accounts.BaseMint = p.allAccountKeys[inner.Accounts[9]]
-	accounts.QuoteMint = p.allAccountKeys[inner.Accounts[10]]
-	accounts.EventAuthority = p.allAccountKeys[inner.Accounts[13]]
-
-	return &accounts
+	// the buy and sell instructions take the same accounts
+	if len(inner.Data) < 8 {
+		return nil
+	}
+	switch {
+	case bytes.Equal(inner.Data[:8], RaydiumLaunchpadBuyExactInDiscriminator[:]),
+		bytes.Equal(inner.Data[:8], RaydiumLaunchpadBuyExactOutDiscriminator[:]),
+		bytes.Equal(inner.Data[:8], RaydiumLaunchpadSellExactInDiscriminator[:]),
+		bytes.Equal(inner.Data[:8], RaydiumLaunchpadSellExactOutDiscriminator[:]):
+	default:
+		return nil
+	}
+	layout, ok := NewRaydiumLaunchpadBuyExactInAccounts(p.instructionAccountKeys(inner))
+	if !ok {
+		return nil
+	}
 
+	return &RaydiumLaunchpadPool{
+		Authority:      layout.Authority,
+		GlobalConfig:   layout.GlobalConfig,
+		PlatformConfig: layout.PlatformConfig,
+		PoolState:      layout.PoolState,
+		BaseVault:      layout.BaseVault,
+		QuoteVault:     layout.QuoteVault,
+		BaseMint:       layout.BaseTokenMint,
+		QuoteMint:      layout.QuoteTokenMint,
+		EventAuthority: layout.EventAuthority,
+	}
 }
 
 func (p *Parser) getRaydiumLaunchpadEvent() *RaydiumLaunchpadEvent { // anchor Self CPI Log
@@ -0,0 +1,45 @@
+package solanaswapgo
+
+import (
+	"fmt"
+	"reflect"
+
+	ag_binary "github.com/gagliardetto/binary"
+)
+
+// decodeEventVersions borsh decodes an event whose program appends fields over time. versions lists,
+// oldest first, the number of leading struct fields of each known version. The fields are decoded in
+// order and the newest version that fits the payload is kept, the bytes after it are returned as extra
+// instead of failing, so events of newer programs still decode.
+func decodeEventVersions(data []byte, event interface{}, versions []int) (version int, extra []byte, err error) {
+	v := reflect.ValueOf(event).Elem()
+	decoder := ag_binary.NewBorshDecoder(data)
+
+	decoded, end := 0, 0
+	for field := 0; field < versions[len(versions)-1]; field++ {
+		if !decoder.HasRemaining() {
+			break
+		}
+		if err := decoder.Decode(v.Field(field).Addr().Interface()); err != nil {
+			break
+		}
+		decoded = field + 1
+		for i, fields := range versions {
+			if fields == decoded {
+				version, end = i+1, int(decoder.Position())
+			}
+		}
+	}
+	if version == 0 {
+		return 0, nil, fmt.Errorf("payload of %d bytes is shorter than any known version", len(data))
+	}
+
+	// fields read past the last complete version belong to a layout we do not know
+	for field := versions[version-1]; field < decoded; field++ {
+		v.Field(field).Set(reflect.Zero(v.Field(field).Type()))
+	}
+	if end < len(data) {
+		extra = data[end:]
+	}
+	return version, extra, nil
+}
@@ -0,0 +1,92 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lonelybeanz/solanaswap-go/idl"
+)
+
+var systemInstructionNames = map[uint32]string{
+	0: "create_account", 1: "assign", 2: "transfer", 3: "create_account_with_seed", 4: "advance_nonce_account",
+	5: "withdraw_nonce_account", 6: "initialize_nonce_account", 7: "authorize_nonce_account", 8: "allocate",
+	9: "allocate_with_seed", 10: "assign_with_seed", 11: "transfer_with_seed", 12: "upgrade_nonce_account",
+}
+
+var tokenInstructionNames = map[byte]string{
+	0: "initialize_mint", 1: "initialize_account", 2: "initialize_multisig", 3: "transfer", 4: "approve", 5: "revoke",
+	6: "set_authority", 7: "mint_to", 8: "burn", 9: "close_account", 10: "freeze_account", 11: "thaw_account",
+	12: "transfer_checked", 13: "approve_checked", 14: "mint_to_checked", 15: "burn_checked",
+	16: "initialize_account2", 17: "sync_native", 18: "initialize_account3", 20: "initialize_mint2",
+	21: "get_account_data_size", 22: "initialize_immutable_owner",
+}
+
+var computeBudgetInstructionNames = map[byte]string{
+	0: "request_units", 1: "request_heap_frame", 2: "set_compute_unit_limit", 3: "set_compute_unit_price",
+	4: "set_loaded_accounts_data_size_limit",
+}
+
+// anchorInstructionNames are the anchor instructions of the supported venues and routers, matched by discriminator
+var anchorInstructionNames = func() map[[8]byte]string {
+	names := make(map[[8]byte]string)
+	for _, name := range []string{
+		"swap", "swap_v2", "swap2", "buy", "sell", "buy_exact_in", "buy_exact_out", "sell_exact_in", "sell_exact_out",
+		"swap_base_input", "swap_base_output", "two_hop_swap", "two_hop_swap_v2", "create", "create_v2", "migrate",
+		"initialize", "initialize_virtual_pool_with_spl_token", "initialize_virtual_pool_with_token2022",
+		"token_mint", "route", "shared_accounts_route", "exact_out_route", "shared_accounts_exact_out_route",
+		"route_with_token_ledger", "shared_accounts_route_with_token_ledger", "proxy_swap",
+		"commission_spl_swap", "commission_sol_swap", "commission_spl_proxy_swap", "commission_sol_proxy_swap",
+		"commission_spl_swap2", "commission_sol_swap2", "fill_order", "flash_fill_order", "place_order",
+		"place_take_order", "open_dca", "open_dca_v2", "close_dca", "withdraw", "deposit", "fulfill_flash_fill",
+		"swap_exact_in", "swap_exact_out", "create_pool", "deposit_stake", "withdraw_stake",
+	} {
+		hash := sha256.Sum256([]byte("global:" + name))
+		var discriminator [8]byte
+		copy(discriminator[:], hash[:8])
+		names[discriminator] = name
+	}
+	return names
+}()
+
+// instructionName decodes the name of an instruction from its program and discriminator, empty when unknown
+func instructionName(programID solana.PublicKey, data []byte) string {
+	switch {
+	case programID.Equals(solana.SystemProgramID):
+		if len(data) >= 4 {
+			return systemInstructionNames[binary.LittleEndian.Uint32(data[:4])]
+		}
+	case programID.Equals(solana.TokenProgramID) || programID.Equals(solana.Token2022ProgramID):
+		if len(data) >= 1 {
+			return tokenInstructionNames[data[0]]
+		}
+	case programID.Equals(solana.ComputeBudget):
+		if len(data) >= 1 {
+			return computeBudgetInstructionNames[data[0]]
+		}
+	case programID.Equals(solana.SPLAssociatedTokenAccountProgramID):
+		if len(data) == 0 || data[0] == 0 {
+			return "create"
+		}
+		if data[0] == 1 {
+			return "create_idempotent"
+		}
+	}
+
+	if registered := idl.Lookup(programID); registered != nil {
+		if name := registered.InstructionName(data); name != "" {
+			return name
+		}
+	}
+
+	if len(data) < 8 {
+		return ""
+	}
+	if bytes.Equal(data[:8], eventIxTag[:]) {
+		return "anchor_event"
+	}
+	var discriminator [8]byte
+	copy(discriminator[:], data[:8])
+	return anchorInstructionNames[discriminator]
+}
@@ -0,0 +1,216 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/mr-tron/base58"
+)
+
+const (
+	programLogPrefix     = "Program log: "
+	programDataLogPrefix = "Program data: "
+	programReturnPrefix  = "Program return: "
+)
+
+// ProgramInvocation is an invocation of a program reconstructed from the transaction logs
+type ProgramInvocation struct {
+	ProgramID         solana.PublicKey
+	Depth             int
+	Logs              []string // every line printed by this invocation, without those of its CPIs
+	Messages          []string // "Program log:" messages
+	Data              [][]byte // "Program data:" payloads, the events of anchor emit!
+	ReturnData        []byte
+	ComputeUnits      uint64 // consumed by the invocation including its CPIs
+	ComputeUnitsLimit uint64
+	Failed            bool
+	Error             string
+	Children          []*ProgramInvocation
+}
+
+// ParseProgramLogs follows the invoke, success, failed and consumed lines of transaction logs and
+// returns the top level invocations, with every line attributed to the invocation that printed it
+func ParseProgramLogs(logs []string) []*ProgramInvocation {
+	var roots []*ProgramInvocation
+	var stack []*ProgramInvocation
+	for _, line := range logs {
+		fields := strings.Fields(line)
+		if len(fields) == 4 && fields[0] == "Program" && fields[2] == "invoke" {
+			programID, err := solana.PublicKeyFromBase58(fields[1])
+			if err != nil {
+				continue
+			}
+			depth, _ := strconv.Atoi(strings.Trim(fields[3], "[]"))
+			invocation := &ProgramInvocation{ProgramID: programID, Depth: depth}
+			if len(stack) == 0 {
+				roots = append(roots, invocation)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, invocation)
+			}
+			stack = append(stack, invocation)
+			continue
+		}
+		if len(stack) == 0 {
+			continue
+		}
+
+		top := stack[len(stack)-1]
+		top.Logs = append(top.Logs, line)
+		switch {
+		case strings.HasPrefix(line, programLogPrefix):
+			top.Messages = append(top.Messages, strings.TrimPrefix(line, programLogPrefix))
+		case strings.HasPrefix(line, programDataLogPrefix):
+			if data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, programDataLogPrefix)); err == nil {
+				top.Data = append(top.Data, data)
+			}
+		case strings.HasPrefix(line, programReturnPrefix):
+			if len(fields) == 4 {
+				top.ReturnData, _ = base64.StdEncoding.DecodeString(fields[3])
+			}
+		case len(fields) >= 7 && fields[0] == "Program" && fields[1] == top.ProgramID.String() && fields[2] == "consumed":
+			// Program X consumed N of M compute units
+			top.ComputeUnits, _ = strconv.ParseUint(fields[3], 10, 64)
+			top.ComputeUnitsLimit, _ = strconv.ParseUint(fields[5], 10, 64)
+		case len(fields) >= 3 && fields[0] == "Program" && fields[1] == top.ProgramID.String() && fields[2] == "success":
+			stack = stack[:len(stack)-1]
+		case len(fields) >= 3 && fields[0] == "Program" && fields[1] == top.ProgramID.String() && strings.HasPrefix(fields[2], "failed"):
+			top.Failed = true
+			top.Error = strings.TrimSpace(strings.TrimPrefix(line, "Program "+fields[1]+" failed"))
+			top.Error = strings.TrimSpace(strings.TrimPrefix(top.Error, ":"))
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return roots
+}
+
+// Walk visits the invocation and its CPIs in execution order
+func (i *ProgramInvocation) Walk(visit func(*ProgramInvocation)) {
+	visit(i)
+	for _, child := range i.Children {
+		child.Walk(visit)
+	}
+}
+
+// ProgramLogs returns the program invocations of the transaction reconstructed from its logs
+func (p *Parser) ProgramLogs() []*ProgramInvocation {
+	if p.txMeta == nil {
+		return nil
+	}
+	return ParseProgramLogs(p.txMeta.LogMessages)
+}
+
+// ComputeUnitsByProgram returns the compute units consumed by each program, excluding those of the programs it invoked
+func (p *Parser) ComputeUnitsByProgram() map[solana.PublicKey]uint64 {
+	usage := make(map[solana.PublicKey]uint64)
+	for _, root := range p.ProgramLogs() {
+		root.Walk(func(invocation *ProgramInvocation) {
+			own := invocation.ComputeUnits
+			for _, child := range invocation.Children {
+				if child.ComputeUnits <= own {
+					own -= child.ComputeUnits
+				}
+			}
+			usage[invocation.ProgramID] += own
+		})
+	}
+	return usage
+}
+
+// FailedInvocation returns the innermost failed invocation, the one whose error made the transaction fail, nil on success
+func (p *Parser) FailedInvocation() *ProgramInvocation {
+	var failed *ProgramInvocation
+	for _, root := range p.ProgramLogs() {
+		root.Walk(func(invocation *ProgramInvocation) {
+			if invocation.Failed && (failed == nil || invocation.Depth > failed.Depth) {
+				failed = invocation
+			}
+		})
+	}
+	return failed
+}
+
+// invocationLogs returns the invocations of the logs in execution order
+func (p *Parser) invocationLogs() []*ProgramInvocation {
+	var invocations []*ProgramInvocation
+	for _, root := range p.ProgramLogs() {
+		root.Walk(func(invocation *ProgramInvocation) {
+			invocations = append(invocations, invocation)
+		})
+	}
+	return invocations
+}
+
+// programDataByInvocation returns, for every invocation of programID in execution order, the decoded
+// "Program data:" payloads emitted directly by that invocation (anchor emit! events).
+func (p *Parser) programDataByInvocation(programID solana.PublicKey) [][][]byte {
+	var result [][][]byte
+	for _, invocation := range p.invocationLogs() {
+		if invocation.ProgramID.Equals(programID) {
+			result = append(result, invocation.Data)
+		}
+	}
+	return result
+}
+
+// invocationOrdinal returns the position of an instruction among all
+// invocations of programID in execution order. innerIndex is -1 for the
+// outer instruction itself.
+func (p *Parser) invocationOrdinal(programID solana.PublicKey, outerIndex int, innerIndex int) int {
+	ordinal := 0
+	for i, outer := range p.txInfo.Message.Instructions {
+		if i == outerIndex && innerIndex < 0 {
+			return ordinal
+		}
+		if p.allAccountKeys[outer.ProgramIDIndex].Equals(programID) {
+			ordinal++
+		}
+		for j, inner := range p.getInnerInstructions(i) {
+			if i == outerIndex && j == innerIndex {
+				return ordinal
+			}
+			if p.allAccountKeys[inner.ProgramIDIndex].Equals(programID) {
+				ordinal++
+			}
+		}
+	}
+	return -1
+}
+
+// eventIxTag prefixes the data of anchor self-CPI event instructions (emit_cpi!)
+var eventIxTag = [8]byte{228, 69, 165, 46, 81, 203, 154, 29}
+
+// anchorEvents returns the event payloads (discriminator followed by data) emitted by programID
+// within an outer instruction, from both self-CPI event instructions and "Program data:" logs.
+func (p *Parser) anchorEvents(programID solana.PublicKey, instructionIndex int) [][]byte {
+	var events [][]byte
+	invocations := p.programDataByInvocation(programID)
+	addLogs := func(innerIndex int) {
+		ordinal := p.invocationOrdinal(programID, instructionIndex, innerIndex)
+		if ordinal >= 0 && ordinal < len(invocations) {
+			events = append(events, invocations[ordinal]...)
+		}
+	}
+
+	if p.allAccountKeys[p.txInfo.Message.Instructions[instructionIndex].ProgramIDIndex].Equals(programID) {
+		addLogs(-1)
+	}
+	for j, inner := range p.getInnerInstructions(instructionIndex) {
+		if !p.allAccountKeys[inner.ProgramIDIndex].Equals(programID) {
+			continue
+		}
+		decodedBytes, err := base58.Decode(inner.Data.String())
+		if err != nil {
+			continue
+		}
+		if len(decodedBytes) >= 16 && bytes.Equal(decodedBytes[:8], eventIxTag[:]) {
+			events = append(events, decodedBytes[8:])
+			continue
+		}
+		addLogs(j)
+	}
+	return events
+}
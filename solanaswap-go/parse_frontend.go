@@ -0,0 +1,80 @@
+package solanaswapgo
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+// frontendEntry returns the catalog entry of a trading bot, the program a swap was sent through is not a venue
+func frontendEntry(entry *CatalogEntry) *CatalogEntry {
+	if entry == nil || entry.Category != CATEGORY_BOT {
+		return nil
+	}
+	return entry
+}
+
+// attributeFrontend sets the frontend of the swap and appends the fees paid to its catalog fee wallets
+func (p *Parser) attributeFrontend(swapInfo *SwapInfo) {
+	catalog := CurrentProgramCatalog()
+
+	for _, outerInstruction := range p.txInfo.Message.Instructions {
+		if bot := frontendEntry(catalog.Program(p.allAccountKeys[outerInstruction.ProgramIDIndex])); bot != nil {
+			swapInfo.Frontend = bot.Name
+			swapInfo.Fees = append(swapInfo.Fees, p.feeWalletFees(bot)...)
+			return
+		}
+	}
+
+	// bots calling the venues directly pay their fee to a known wallet in a separate instruction
+	for i, outerInstruction := range p.txInfo.Message.Instructions {
+		instructions := []solana.CompiledInstruction{outerInstruction}
+		for _, inner := range p.getInnerInstructions(i) {
+			instructions = append(instructions, p.convertRPCToSolanaInstruction(inner))
+		}
+		for _, inst := range instructions {
+			transfer := p.parseRouteMovement(inst)
+			if transfer == nil {
+				continue
+			}
+			if bot := frontendEntry(catalog.FeeWallet(p.transferRecipient(transfer))); bot != nil {
+				swapInfo.Frontend = bot.Name
+				swapInfo.Fees = append(swapInfo.Fees, p.feeWalletFees(bot)...)
+				return
+			}
+		}
+	}
+}
+
+// feeWalletFees returns every transfer of the transaction paying a fee wallet of the bot
+func (p *Parser) feeWalletFees(bot *CatalogEntry) []SwapFee {
+	var fees []SwapFee
+	for i, outerInstruction := range p.txInfo.Message.Instructions {
+		instructions := []solana.CompiledInstruction{outerInstruction}
+		for _, inner := range p.getInnerInstructions(i) {
+			instructions = append(instructions, p.convertRPCToSolanaInstruction(inner))
+		}
+		for _, inst := range instructions {
+			transfer := p.parseRouteMovement(inst)
+			if transfer == nil {
+				continue
+			}
+			if recipient := p.transferRecipient(transfer); CurrentProgramCatalog().FeeWallet(recipient) == bot {
+				fees = append(fees, SwapFee{
+					Type:      "bot",
+					Mint:      solana.MustPublicKeyFromBase58(transfer.mint),
+					Amount:    transfer.amount,
+					Recipient: recipient,
+				})
+			}
+		}
+	}
+	return fees
+}
+
+// transferRecipient returns the wallet receiving a transfer, the owner for token accounts
+func (p *Parser) transferRecipient(transfer *tokenMovement) solana.PublicKey {
+	destination := solana.MustPublicKeyFromBase58(transfer.destination)
+	if owner := p.tokenAccountOwner(destination); !owner.IsZero() {
+		return owner
+	}
+	return destination
+}
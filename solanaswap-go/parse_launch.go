@@ -0,0 +1,299 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	RaydiumLaunchpadInitializeDiscriminator          = [8]byte{175, 175, 109, 31, 13, 152, 155, 237}
+	RaydiumLaunchpadInitializeV2Discriminator        = [8]byte{67, 153, 175, 39, 218, 16, 38, 32}
+	RaydiumLaunchpadInitializeToken2022Discriminator = [8]byte{37, 190, 126, 222, 44, 154, 171, 17}
+	RaydiumLaunchpadTradeEventDiscriminator          = [8]byte{189, 219, 127, 211, 78, 230, 97, 238}
+
+	MeteoraDbcInitializeSplDiscriminator       = [8]byte{140, 85, 215, 176, 102, 54, 104, 79}
+	MeteoraDbcInitializeToken2022Discriminator = [8]byte{169, 118, 51, 78, 145, 110, 220, 155}
+	MeteoraDbcSwapEventDiscriminator           = [8]byte{27, 60, 21, 213, 138, 170, 187, 147}
+
+	MoonshotTokenMintDiscriminator = [8]byte{3, 44, 164, 184, 123, 13, 245, 179}
+)
+
+// LaunchInfo is a token launched on a launchpad, the same for every platform
+type LaunchInfo struct {
+	Platform     SwapType
+	Instruction  string
+	Mint         solana.PublicKey
+	QuoteMint    solana.PublicKey
+	TokenProgram solana.PublicKey
+	Decimals     uint8
+	TotalSupply  uint64 // zero when the launch does not set it
+	Name         string
+	Symbol       string
+	Uri          string
+	Creator      solana.PublicKey
+	Pool         solana.PublicKey // bonding curve, pool state, virtual pool or curve account
+	// initial virtual reserves of the curve, zero when the launchpad does not report them
+	BaseReserves  uint64
+	QuoteReserves uint64
+	DevBuy        *LaunchDevBuy // first buy of the token in the same transaction, nil when none
+	Data          interface{}   // the platform create event or instruction args
+}
+
+// LaunchDevBuy is the buy made in the launch transaction, Buyer is the fee payer when the platform does not name it
+type LaunchDevBuy struct {
+	Buyer       solana.PublicKey
+	TokenAmount uint64
+	QuoteAmount uint64
+}
+
+// RaydiumLaunchpadMintParams are the token arguments of the Raydium Launchpad initialize instructions
+type RaydiumLaunchpadMintParams struct {
+	Decimals uint8
+	Name     string
+	Symbol   string
+	Uri      string
+}
+
+// MeteoraDbcPoolParams are the arguments of the Meteora DBC initialize_virtual_pool instructions
+type MeteoraDbcPoolParams struct {
+	Name   string
+	Symbol string
+	Uri    string
+}
+
+// MoonshotTokenMintParams are the arguments of the Moonshot tokenMint instruction
+type MoonshotTokenMintParams struct {
+	Name               string
+	Symbol             string
+	Uri                string
+	Decimals           uint8
+	CollateralCurrency uint8
+	Amount             uint64
+	CurveType          uint8
+	MigrationTarget    uint8
+}
+
+// ParseTransactionForLaunch returns the first token launched by the transaction on pump.fun, Raydium Launchpad,
+// Meteora DBC or Moonshot, by an outer instruction or through CPI
+func (p *Parser) ParseTransactionForLaunch() (*LaunchInfo, error) {
+	launches := p.getLaunches()
+	if len(launches) == 0 {
+		return nil, fmt.Errorf("no launch found")
+	}
+	return launches[0], nil
+}
+
+func (p *Parser) getLaunches() []*LaunchInfo {
+	var launches []*LaunchInfo
+	for _, create := range p.getPumpfunLaunches() {
+		launches = append(launches, pumpfunLaunch(create))
+	}
+
+	p.forEachInstruction(func(inst solana.CompiledInstruction) {
+		if len(inst.Data) < 8 {
+			return
+		}
+		var launch *LaunchInfo
+		var err error
+		switch programID := p.allAccountKeys[inst.ProgramIDIndex]; {
+		case programID.Equals(RAYDIUM_Launchpad_PROGRAM_ID):
+			launch, err = p.raydiumLaunchpadLaunch(inst)
+		case programID.Equals(METEORA_DBC_PROGRAM_ID):
+			launch, err = p.meteoraDbcLaunch(inst)
+		case programID.Equals(MOONSHOT_PROGRAM_ID):
+			launch, err = p.moonshotLaunch(inst)
+		}
+		if err != nil {
+			p.Log.Errorf("error processing launch: %s", err)
+			return
+		}
+		if launch != nil {
+			if launch.Decimals == 0 {
+				launch.Decimals = p.splDecimalsMap[launch.Mint.String()]
+			}
+			launches = append(launches, launch)
+		}
+	})
+
+	p.matchLaunchDevBuys(launches)
+	return launches
+}
+
+func pumpfunLaunch(create *PumpfunCreateEvent) *LaunchInfo {
+	launch := &LaunchInfo{
+		Platform:      PUMP_FUN,
+		Instruction:   create.Instruction,
+		Mint:          create.Mint,
+		QuoteMint:     NATIVE_SOL_MINT_PROGRAM_ID,
+		TokenProgram:  create.TokenProgram,
+		Decimals:      6,
+		TotalSupply:   create.TokenTotalSupply,
+		Name:          create.Name,
+		Symbol:        create.Symbol,
+		Uri:           create.Uri,
+		Creator:       create.Creator,
+		Pool:          create.BondingCurve,
+		BaseReserves:  create.VirtualTokenReserves,
+		QuoteReserves: create.VirtualSolReserves,
+		Data:          create,
+	}
+	if launch.Creator.IsZero() {
+		launch.Creator = create.User
+	}
+	if create.DevBuy != nil {
+		launch.DevBuy = &LaunchDevBuy{Buyer: create.DevBuy.User, TokenAmount: create.DevBuy.TokenAmount, QuoteAmount: create.DevBuy.SolAmount}
+	}
+	return launch
+}
+
+// raydiumLaunchpadLaunch decodes the initialize, initialize_v2 and initialize_with_token_2022 instructions, which
+// share their first accounts and arguments
+func (p *Parser) raydiumLaunchpadLaunch(inst solana.CompiledInstruction) (*LaunchInfo, error) {
+	launch := &LaunchInfo{Platform: RAYDIUM_Launchpad, TokenProgram: solana.TokenProgramID}
+	switch {
+	case bytes.Equal(inst.Data[:8], RaydiumLaunchpadInitializeDiscriminator[:]):
+		launch.Instruction = "initialize"
+	case bytes.Equal(inst.Data[:8], RaydiumLaunchpadInitializeV2Discriminator[:]):
+		launch.Instruction = "initialize_v2"
+	case bytes.Equal(inst.Data[:8], RaydiumLaunchpadInitializeToken2022Discriminator[:]):
+		launch.Instruction, launch.TokenProgram = "initialize_with_token_2022", solana.Token2022ProgramID
+	default:
+		return nil, nil
+	}
+	accounts := p.instructionAccountKeys(inst)
+	if len(accounts) < 10 {
+		return nil, fmt.Errorf("Raydium Launchpad %s has %d accounts", launch.Instruction, len(accounts))
+	}
+
+	decoder := ag_binary.NewBorshDecoder(inst.Data[8:])
+	var params RaydiumLaunchpadMintParams
+	if err := decoder.Decode(&params); err != nil {
+		return nil, fmt.Errorf("error unmarshaling Raydium Launchpad %s: %s", launch.Instruction, err)
+	}
+	// every curve variant starts with the supply
+	if _, err := decoder.ReadUint8(); err == nil {
+		launch.TotalSupply, _ = decoder.ReadUint64(binary.LittleEndian)
+	}
+
+	launch.Creator, launch.Pool = accounts[1], accounts[5]
+	launch.Mint, launch.QuoteMint = accounts[6], accounts[7]
+	launch.Decimals, launch.Name, launch.Symbol, launch.Uri = params.Decimals, params.Name, params.Symbol, params.Uri
+	launch.Data = &params
+	return launch, nil
+}
+
+// meteoraDbcLaunch decodes the initialize_virtual_pool_with_spl_token and initialize_virtual_pool_with_token2022
+// instructions, which share their first accounts and their arguments
+func (p *Parser) meteoraDbcLaunch(inst solana.CompiledInstruction) (*LaunchInfo, error) {
+	launch := &LaunchInfo{Platform: METEORA_DBC}
+	switch {
+	case bytes.Equal(inst.Data[:8], MeteoraDbcInitializeSplDiscriminator[:]):
+		launch.Instruction, launch.TokenProgram = "initialize_virtual_pool_with_spl_token", solana.TokenProgramID
+	case bytes.Equal(inst.Data[:8], MeteoraDbcInitializeToken2022Discriminator[:]):
+		launch.Instruction, launch.TokenProgram = "initialize_virtual_pool_with_token2022", solana.Token2022ProgramID
+	default:
+		return nil, nil
+	}
+	accounts := p.instructionAccountKeys(inst)
+	if len(accounts) < 8 {
+		return nil, fmt.Errorf("Meteora DBC %s has %d accounts", launch.Instruction, len(accounts))
+	}
+
+	var params MeteoraDbcPoolParams
+	if err := ag_binary.NewBorshDecoder(inst.Data[8:]).Decode(&params); err != nil {
+		return nil, fmt.Errorf("error unmarshaling Meteora DBC %s: %s", launch.Instruction, err)
+	}
+
+	launch.Creator, launch.Mint, launch.QuoteMint, launch.Pool = accounts[2], accounts[3], accounts[4], accounts[5]
+	launch.Name, launch.Symbol, launch.Uri = params.Name, params.Symbol, params.Uri
+	launch.Data = &params
+	return launch, nil
+}
+
+func (p *Parser) moonshotLaunch(inst solana.CompiledInstruction) (*LaunchInfo, error) {
+	if !bytes.Equal(inst.Data[:8], MoonshotTokenMintDiscriminator[:]) {
+		return nil, nil
+	}
+	accounts := p.instructionAccountKeys(inst)
+	if len(accounts) < 8 {
+		return nil, fmt.Errorf("Moonshot tokenMint has %d accounts", len(accounts))
+	}
+
+	var params MoonshotTokenMintParams
+	if err := ag_binary.NewBorshDecoder(inst.Data[8:]).Decode(&params); err != nil {
+		return nil, fmt.Errorf("error unmarshaling Moonshot tokenMint: %s", err)
+	}
+
+	return &LaunchInfo{
+		Platform:     MOONSHOT,
+		Instruction:  "token_mint",
+		Mint:         accounts[3],
+		QuoteMint:    NATIVE_SOL_MINT_PROGRAM_ID,
+		TokenProgram: accounts[7],
+		Decimals:     params.Decimals,
+		TotalSupply:  params.Amount,
+		Name:         params.Name,
+		Symbol:       params.Symbol,
+		Uri:          params.Uri,
+		Creator:      accounts[0],
+		Pool:         accounts[2],
+		Data:         &params,
+	}, nil
+}
+
+// matchLaunchDevBuys sets the dev buy of the Raydium Launchpad, Meteora DBC and Moonshot launches to the first
+// trade of their pool in the transaction, a new pool can only be bought first
+func (p *Parser) matchLaunchDevBuys(launches []*LaunchInfo) {
+	pending := func(platform SwapType, pool solana.PublicKey) *LaunchInfo {
+		for _, launch := range launches {
+			if launch.Platform == platform && launch.DevBuy == nil && launch.Pool.Equals(pool) {
+				return launch
+			}
+		}
+		return nil
+	}
+
+	p.forEachInstruction(func(inst solana.CompiledInstruction) {
+		if len(inst.Data) < 16 || !bytes.Equal(inst.Data[:8], eventIxTag[:]) {
+			return
+		}
+		switch programID := p.allAccountKeys[inst.ProgramIDIndex]; {
+		case programID.Equals(RAYDIUM_Launchpad_PROGRAM_ID) && bytes.Equal(inst.Data[8:16], RaydiumLaunchpadTradeEventDiscriminator[:]):
+			event, err := handleRaydiumLaunchpadEvent(ag_binary.NewBorshDecoder(inst.Data[16:]))
+			if err != nil {
+				return
+			}
+			if launch := pending(RAYDIUM_Launchpad, event.PoolState); launch != nil {
+				launch.BaseReserves, launch.QuoteReserves = event.VirtualBase, event.VirtualQuote
+				launch.DevBuy = &LaunchDevBuy{Buyer: p.allAccountKeys[0], TokenAmount: event.AmountOut, QuoteAmount: event.AmountIn}
+			}
+		case programID.Equals(METEORA_DBC_PROGRAM_ID) && bytes.Equal(inst.Data[8:16], MeteoraDbcSwapEventDiscriminator[:]):
+			event, err := handleMeteoraDbcEvent(ag_binary.NewBorshDecoder(inst.Data[16:]))
+			if err != nil || event.TradeDirection != 1 { // quote to base
+				return
+			}
+			if launch := pending(METEORA_DBC, event.Pool); launch != nil {
+				launch.DevBuy = &LaunchDevBuy{Buyer: p.allAccountKeys[0], TokenAmount: event.SwapResult.OutputAmount, QuoteAmount: event.SwapResult.ActualInputAmount}
+			}
+		}
+	})
+
+	for i, outerInstruction := range p.txInfo.Message.Instructions {
+		if !p.allAccountKeys[outerInstruction.ProgramIDIndex].Equals(MOONSHOT_PROGRAM_ID) && !p.innerContainsProgram(i, MOONSHOT_PROGRAM_ID) {
+			continue
+		}
+		for _, swap := range p.processMoonshotSwaps(i) {
+			trade, ok := swap.Data.(*MoonshotTradeInstructionWithMint)
+			if !ok || trade.TradeType != TradeTypeBuy {
+				continue
+			}
+			if launch := pending(MOONSHOT, trade.CurveAccount); launch != nil {
+				launch.DevBuy = &LaunchDevBuy{Buyer: trade.Sender, TokenAmount: trade.TokenAmount, QuoteAmount: trade.CollateralAmount}
+			}
+		}
+	}
+}
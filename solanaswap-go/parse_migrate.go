@@ -0,0 +1,250 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var (
+	PumpfunMigrateDiscriminator                       = [8]byte{155, 234, 231, 146, 236, 158, 162, 30}
+	PumpfunCompletePumpAmmMigrationEventDiscriminator = [8]byte{189, 233, 93, 185, 92, 148, 234, 148}
+	PumpAmmCreatePoolDiscriminator                    = [8]byte{233, 146, 209, 142, 207, 104, 64, 188}
+	RaydiumLaunchpadMigrateToCpswapDiscriminator      = [8]byte{136, 92, 200, 103, 28, 218, 144, 140}
+	RaydiumCPMMInitializeDiscriminator                = [8]byte{175, 175, 109, 31, 13, 152, 155, 237}
+	MeteoraDbcMigrateDammDiscriminator                = [8]byte{27, 1, 48, 22, 180, 63, 118, 217}
+	MeteoraDbcMigrateDammV2Discriminator              = [8]byte{156, 169, 230, 103, 53, 228, 80, 64}
+)
+
+// MigrateInfo is a bonding curve graduating to an AMM pool
+type MigrateInfo struct {
+	Platform    SwapType // launchpad of the curve
+	Destination SwapType // AMM of the new pool
+	Instruction string
+	Curve       solana.PublicKey // bonding curve, pool state or virtual pool the liquidity left
+	Pool        solana.PublicKey
+	BaseMint    solana.PublicKey
+	QuoteMint   solana.PublicKey
+	// liquidity deposited into the pool vaults
+	BaseAmount   uint64
+	QuoteAmount  uint64
+	MigrationFee uint64 // quote paid to the launchpad, zero when it does not report it
+	// LP tokens minted for the migrated liquidity, DAMM v2 pools use position NFTs and have no LP mint
+	LpMint      solana.PublicKey
+	LpAmount    uint64
+	LpRecipient solana.PublicKey // token account the LP tokens were minted to
+	LpBurned    uint64           // LP tokens burned in the same transaction
+	PoolData    interface{}      // migration event of the launchpad when it emits one
+}
+
+// PumpfunCompletePumpAmmMigrationEvent is emitted by the pump.fun migrate instruction
+type PumpfunCompletePumpAmmMigrationEvent struct {
+	User             solana.PublicKey
+	Mint             solana.PublicKey
+	MintAmount       uint64
+	SolAmount        uint64
+	PoolMigrationFee uint64
+	BondingCurve     solana.PublicKey
+	Timestamp        int64
+	Pool             solana.PublicKey
+}
+
+// ParseTransactionForMigrate returns the first bonding curve migration of the transaction: pump.fun to PumpAmm,
+// Raydium Launchpad to CPMM, or Meteora DBC to DAMM v1 or v2
+func (p *Parser) ParseTransactionForMigrate() (*MigrateInfo, error) {
+	for i := range p.txInfo.Message.Instructions {
+		root, _ := p.invocationTree(i)
+		var migration *MigrateInfo
+		var err error
+		root.walk(func(node *invocation) {
+			if migration != nil || err != nil || len(node.Instruction.Data) < 8 {
+				return
+			}
+			migration, err = p.parseMigration(root, node)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if migration != nil {
+			p.fillMigrationLiquidity(migration)
+			return migration, nil
+		}
+	}
+	return nil, fmt.Errorf("no migration found")
+}
+
+// parseMigration decodes node when it is a migrate instruction, the pool it creates is looked up among its
+// CPIs, or anywhere in the outer instruction when the transaction has no stack heights
+func (p *Parser) parseMigration(root *invocation, node *invocation) (*MigrateInfo, error) {
+	data := node.Instruction.Data
+	accounts := p.instructionAccountKeys(node.Instruction)
+	switch {
+	case node.ProgramID.Equals(PUMP_FUN_PROGRAM_ID) && bytes.Equal(data[:8], PumpfunMigrateDiscriminator[:]):
+		create := findInvocation(root, node, PUMP_AMM_PROGRAM_ID, PumpAmmCreatePoolDiscriminator)
+		if create == nil || len(accounts) < 4 {
+			return nil, fmt.Errorf("pump.fun migrate without PumpAmm create_pool")
+		}
+		pool := p.instructionAccountKeys(create.Instruction)
+		if len(pool) < 11 {
+			return nil, fmt.Errorf("PumpAmm create_pool has %d accounts", len(pool))
+		}
+		migration := &MigrateInfo{
+			Platform: PUMP_FUN, Destination: PUMP_SWAP, Instruction: "migrate",
+			Curve: accounts[3], Pool: pool[0], BaseMint: pool[3], QuoteMint: pool[4],
+			LpMint: pool[5],
+		}
+		if event := p.pumpfunMigrationEvent(node); event != nil {
+			migration.MigrationFee = event.PoolMigrationFee
+			migration.PoolData = event
+		}
+		migration.BaseAmount, migration.QuoteAmount = p.tokenBalanceIncrease(pool[9]), p.tokenBalanceIncrease(pool[10])
+		return migration, nil
+
+	case node.ProgramID.Equals(RAYDIUM_Launchpad_PROGRAM_ID) && bytes.Equal(data[:8], RaydiumLaunchpadMigrateToCpswapDiscriminator[:]):
+		create := findInvocation(root, node, RAYDIUM_CPMM_PROGRAM_ID, RaydiumCPMMInitializeDiscriminator)
+		if create == nil {
+			return nil, fmt.Errorf("Raydium Launchpad migrate_to_cpswap without CPMM initialize")
+		}
+		pool := p.instructionAccountKeys(create.Instruction)
+		if len(pool) < 12 {
+			return nil, fmt.Errorf("Raydium CPMM initialize has %d accounts", len(pool))
+		}
+		migration := &MigrateInfo{
+			Platform: RAYDIUM_Launchpad, Destination: RAYDIUM_CPMM, Instruction: "migrate_to_cpswap",
+			Pool: pool[3], LpMint: pool[6],
+		}
+		// CPMM sorts the mints, the launchpad pool address tells which one is the base
+		token0, token1 := pool[4], pool[5]
+		amount0, amount1 := p.tokenBalanceIncrease(pool[10]), p.tokenBalanceIncrease(pool[11])
+		migration.BaseMint, migration.QuoteMint = token0, token1
+		migration.BaseAmount, migration.QuoteAmount = amount0, amount1
+		if curve, ok := p.raydiumLaunchpadPoolAddress(token0, token1); ok {
+			migration.Curve = curve
+		} else if curve, ok := p.raydiumLaunchpadPoolAddress(token1, token0); ok {
+			migration.Curve = curve
+			migration.BaseMint, migration.QuoteMint = token1, token0
+			migration.BaseAmount, migration.QuoteAmount = amount1, amount0
+		}
+		return migration, nil
+
+	case node.ProgramID.Equals(METEORA_DBC_PROGRAM_ID) && bytes.Equal(data[:8], MeteoraDbcMigrateDammV2Discriminator[:]):
+		if len(accounts) < 17 {
+			return nil, fmt.Errorf("Meteora DBC migration_damm_v2 has %d accounts", len(accounts))
+		}
+		return &MigrateInfo{
+			Platform: METEORA_DBC, Destination: METEORA_DAMM_V2, Instruction: "migration_damm_v2",
+			Curve: accounts[0], Pool: accounts[4], BaseMint: accounts[13], QuoteMint: accounts[14],
+			BaseAmount: p.tokenBalanceIncrease(accounts[15]), QuoteAmount: p.tokenBalanceIncrease(accounts[16]),
+		}, nil
+
+	case node.ProgramID.Equals(METEORA_DBC_PROGRAM_ID) && bytes.Equal(data[:8], MeteoraDbcMigrateDammDiscriminator[:]):
+		if len(accounts) < 18 {
+			return nil, fmt.Errorf("Meteora DBC migrate_meteora_damm has %d accounts", len(accounts))
+		}
+		// DAMM v1 deposits into the shared Meteora vaults, the growth of their token accounts is the migrated liquidity
+		return &MigrateInfo{
+			Platform: METEORA_DBC, Destination: METEORA_DAMM, Instruction: "migrate_meteora_damm",
+			Curve: accounts[0], Pool: accounts[4], BaseMint: accounts[7], QuoteMint: accounts[8],
+			BaseAmount: p.tokenBalanceIncrease(accounts[11]), QuoteAmount: p.tokenBalanceIncrease(accounts[12]),
+			LpMint: accounts[6],
+		}, nil
+	}
+	return nil, nil
+}
+
+// findInvocation returns the first instruction of programID starting with discriminator among the CPIs of
+// node, or else in the whole tree of root
+func findInvocation(root *invocation, node *invocation, programID solana.PublicKey, discriminator [8]byte) *invocation {
+	for _, tree := range []*invocation{node, root} {
+		var found *invocation
+		tree.walk(func(n *invocation) {
+			if found == nil && n.ProgramID.Equals(programID) && len(n.Instruction.Data) >= 8 && bytes.Equal(n.Instruction.Data[:8], discriminator[:]) {
+				found = n
+			}
+		})
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// pumpfunMigrationEvent returns the CompletePumpAmmMigrationEvent emitted under the migrate instruction
+func (p *Parser) pumpfunMigrationEvent(node *invocation) *PumpfunCompletePumpAmmMigrationEvent {
+	var event *PumpfunCompletePumpAmmMigrationEvent
+	node.walk(func(n *invocation) {
+		data := n.Instruction.Data
+		if event != nil || !n.ProgramID.Equals(PUMP_FUN_PROGRAM_ID) || len(data) < 16 ||
+			!bytes.Equal(data[:8], eventIxTag[:]) || !bytes.Equal(data[8:16], PumpfunCompletePumpAmmMigrationEventDiscriminator[:]) {
+			return
+		}
+		var decoded PumpfunCompletePumpAmmMigrationEvent
+		if err := ag_binary.NewBorshDecoder(data[16:]).Decode(&decoded); err != nil {
+			p.Log.Errorf("error unmarshaling CompletePumpAmmMigrationEvent: %s", err)
+			return
+		}
+		event = &decoded
+	})
+	return event
+}
+
+// raydiumLaunchpadPoolAddress derives the launchpad pool of a mint pair, ok is false when the transaction does not use it
+func (p *Parser) raydiumLaunchpadPoolAddress(baseMint solana.PublicKey, quoteMint solana.PublicKey) (solana.PublicKey, bool) {
+	pool, _, err := solana.FindProgramAddress([][]byte{[]byte("pool"), baseMint.Bytes(), quoteMint.Bytes()}, RAYDIUM_Launchpad_PROGRAM_ID)
+	if err != nil {
+		return solana.PublicKey{}, false
+	}
+	return pool, p.allAccountKeys.Contains(pool)
+}
+
+// fillMigrationLiquidity sets the LP tokens minted and burned for the migrated liquidity
+func (p *Parser) fillMigrationLiquidity(migration *MigrateInfo) {
+	if migration.LpMint.IsZero() {
+		return
+	}
+	p.forEachInstruction(func(inst solana.CompiledInstruction) {
+		programID := p.allAccountKeys[inst.ProgramIDIndex]
+		if (!programID.Equals(solana.TokenProgramID) && !programID.Equals(solana.Token2022ProgramID)) || len(inst.Data) < 9 || len(inst.Accounts) < 2 {
+			return
+		}
+		accounts := p.instructionAccountKeys(inst)
+		amount := binary.LittleEndian.Uint64(inst.Data[1:9])
+		switch inst.Data[0] {
+		case 7, 14: // mint_to, mint_to_checked: mint, account, authority
+			if accounts[0].Equals(migration.LpMint) {
+				migration.LpAmount += amount
+				migration.LpRecipient = accounts[1]
+			}
+		case 8, 15: // burn, burn_checked: account, mint, owner
+			if accounts[1].Equals(migration.LpMint) {
+				migration.LpBurned += amount
+			}
+		}
+	})
+}
+
+// tokenBalanceIncrease returns how much the balance of a token account grew in the transaction
+func (p *Parser) tokenBalanceIncrease(account solana.PublicKey) uint64 {
+	if p.txMeta == nil {
+		return 0
+	}
+	balance := func(balances []rpc.TokenBalance) uint64 {
+		for _, b := range balances {
+			if int(b.AccountIndex) < len(p.allAccountKeys) && p.allAccountKeys[b.AccountIndex].Equals(account) && b.UiTokenAmount != nil {
+				amount, _ := strconv.ParseUint(b.UiTokenAmount.Amount, 10, 64)
+				return amount
+			}
+		}
+		return 0
+	}
+	pre, post := balance(p.txMeta.PreTokenBalances), balance(p.txMeta.PostTokenBalances)
+	if post < pre {
+		return 0
+	}
+	return post - pre
+}
@@ -11,10 +11,16 @@ import (
 )
 
 type MoonshotTradeInstructionWithMint struct {
-	TokenAmount      uint64
-	CollateralAmount uint64
-	Mint             solana.PublicKey
-	TradeType        TradeType
+	TokenAmount       uint64
+	CollateralAmount  uint64
+	Mint              solana.PublicKey
+	TradeType         TradeType
+	Sender            solana.PublicKey
+	CurveAccount      solana.PublicKey
+	CurveTokenAccount solana.PublicKey
+	DexFee            uint64
+	HelioFee          uint64
+	Allocation        uint64
 }
 
 type TradeType int
@@ -27,33 +33,72 @@ const (
 var (
 	MOONSHOT_BUY_INSTRUCTION  = ag_binary.TypeID([8]byte{102, 6, 61, 18, 1, 218, 235, 234})
 	MOONSHOT_SELL_INSTRUCTION = ag_binary.TypeID([8]byte{51, 230, 133, 164, 1, 127, 131, 173})
+
+	MoonshotTradeEventDiscriminator = [8]byte{189, 219, 127, 211, 78, 230, 97, 238}
 )
 
-// processMoonshotSwaps processes all Moonshot swap instructions in the transaction
-func (p *Parser) processMoonshotSwaps() []SwapData {
+// MoonshotTradeEvent is emitted through the program logs (anchor emit!) on every buy and sell
+type MoonshotTradeEvent struct {
+	Amount           uint64
+	CollateralAmount uint64
+	DexFee           uint64
+	HelioFee         uint64
+	Allocation       uint64
+	Curve            solana.PublicKey
+	CostToken        solana.PublicKey
+	Sender           solana.PublicKey
+	Type             uint8
+	Label            string
+}
+
+type MoonshotPool struct {
+	CurveAccount      solana.PublicKey
+	CurveTokenAccount solana.PublicKey
+	DexFeeAccount     solana.PublicKey
+	HelioFeeAccount   solana.PublicKey
+	Mint              solana.PublicKey
+	ConfigAccount     solana.PublicKey
+	Allocation        uint64
+}
+
+// processMoonshotSwaps processes the Moonshot trades of an outer instruction, including trades made through CPI
+func (p *Parser) processMoonshotSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
 
-	// for _, instruction := range p.txInfo.Message.Instructions {
-	// 	if p.isMoonshotTrade(instruction) {
-	// 		fmt.Errorf("moonshot trade instruction %s",p.txInfo.Signatures[0].String())
-	// 		swapData, err := p.parseMoonshotTradeInstruction(instruction)
-	// 		if err != nil {
-	// 			continue
-	// 		}
-	// 		swaps = append(swaps, *swapData)
-	// 	}
-	// }
+	outerInstruction := p.txInfo.Message.Instructions[instructionIndex]
+	if p.isMoonshotTrade(outerInstruction) {
+		swapData, err := p.parseMoonshotTradeInstruction(outerInstruction, p.invocationOrdinal(MOONSHOT_PROGRAM_ID, instructionIndex, -1))
+		if err != nil {
+			p.Log.Errorf("error processing Moonshot trade: %s", err)
+		} else {
+			swaps = append(swaps, *swapData)
+		}
+	}
+
+	for j, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		inst := p.convertRPCToSolanaInstruction(innerInstruction)
+		if !p.isMoonshotTrade(inst) {
+			continue
+		}
+		swapData, err := p.parseMoonshotTradeInstruction(inst, p.invocationOrdinal(MOONSHOT_PROGRAM_ID, instructionIndex, j))
+		if err != nil {
+			p.Log.Errorf("error processing Moonshot trade: %s", err)
+			continue
+		}
+		swaps = append(swaps, *swapData)
+	}
 
 	return swaps
 }
 
 // isMoonshotTrade checks if the instruction is a Moonshot trade
 func (p *Parser) isMoonshotTrade(instruction solana.CompiledInstruction) bool {
-	return p.txInfo.Message.AccountKeys[instruction.ProgramIDIndex].Equals(MOONSHOT_PROGRAM_ID) && len(instruction.Data) == 33 && len(instruction.Accounts) == 11
+	return p.allAccountKeys[instruction.ProgramIDIndex].Equals(MOONSHOT_PROGRAM_ID) && len(instruction.Data) == 33 && len(instruction.Accounts) == 11
 }
 
-// parseMoonshotTradeInstruction parses a Moonshot trade instruction
-func (p *Parser) parseMoonshotTradeInstruction(instruction solana.CompiledInstruction) (*SwapData, error) {
+// parseMoonshotTradeInstruction parses a Moonshot trade instruction, invocation is the
+// position of the instruction among all Moonshot invocations and is used to find its TradeEvent
+func (p *Parser) parseMoonshotTradeInstruction(instruction solana.CompiledInstruction, invocation int) (*SwapData, error) {
 	decodedBytes, err := base58.Decode(instruction.Data.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode base58 instruction data: %v", err)
@@ -71,23 +116,35 @@ func (p *Parser) parseMoonshotTradeInstruction(instruction solana.CompiledInstru
 		return nil, fmt.Errorf("unknown moonshot trade instruction")
 	}
 
-	moonshotTokenMint := p.txInfo.Message.AccountKeys[instruction.Accounts[6]]
-
-	moonshotTokenBalanceChanges, err := p.getTokenBalanceChanges(moonshotTokenMint)
-	if err != nil {
-		return nil, fmt.Errorf("error getting moonshot token balance changes: %s", err)
+	instructionWithMint := &MoonshotTradeInstructionWithMint{
+		Mint:              p.allAccountKeys[instruction.Accounts[6]],
+		TradeType:         tradeType,
+		Sender:            p.allAccountKeys[instruction.Accounts[0]],
+		CurveAccount:      p.allAccountKeys[instruction.Accounts[2]],
+		CurveTokenAccount: p.allAccountKeys[instruction.Accounts[3]],
 	}
 
-	nativeSolBalanceChanges, err := p.getTokenBalanceChanges(NATIVE_SOL_MINT_PROGRAM_ID)
-	if err != nil {
-		return nil, fmt.Errorf("error getting native sol balance changes: %s", err)
-	}
+	if event := p.getMoonshotTradeEvent(invocation); event != nil && event.Curve.Equals(instructionWithMint.CurveAccount) {
+		instructionWithMint.TokenAmount = event.Amount
+		instructionWithMint.CollateralAmount = event.CollateralAmount
+		instructionWithMint.DexFee = event.DexFee
+		instructionWithMint.HelioFee = event.HelioFee
+		instructionWithMint.Allocation = event.Allocation
+		instructionWithMint.Sender = event.Sender
+	} else {
+		// logs are missing or truncated, fall back to the signer balance changes
+		moonshotTokenBalanceChanges, err := p.getTokenBalanceChanges(instructionWithMint.Mint)
+		if err != nil {
+			return nil, fmt.Errorf("error getting moonshot token balance changes: %s", err)
+		}
 
-	instructionWithMint := &MoonshotTradeInstructionWithMint{
-		TokenAmount:      uint64(abs(moonshotTokenBalanceChanges)),
-		CollateralAmount: uint64(abs(nativeSolBalanceChanges)),
-		Mint:             moonshotTokenMint,
-		TradeType:        tradeType,
+		nativeSolBalanceChanges, err := p.getTokenBalanceChanges(NATIVE_SOL_MINT_PROGRAM_ID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting native sol balance changes: %s", err)
+		}
+
+		instructionWithMint.TokenAmount = uint64(abs(moonshotTokenBalanceChanges))
+		instructionWithMint.CollateralAmount = uint64(abs(nativeSolBalanceChanges))
 	}
 
 	return &SwapData{
@@ -96,6 +153,61 @@ func (p *Parser) parseMoonshotTradeInstruction(instruction solana.CompiledInstru
 	}, nil
 }
 
+// getMoonshotTradeEvent returns the TradeEvent logged by the given Moonshot invocation
+func (p *Parser) getMoonshotTradeEvent(invocation int) *MoonshotTradeEvent {
+	if p.moonshotData == nil {
+		// every trade of the transaction looks its event up, the logs are parsed once
+		p.moonshotData = append([][][]byte{}, p.programDataByInvocation(MOONSHOT_PROGRAM_ID)...)
+	}
+	if invocation < 0 || invocation >= len(p.moonshotData) {
+		return nil
+	}
+	for _, data := range p.moonshotData[invocation] {
+		if len(data) < 8 || !bytes.Equal(data[:8], MoonshotTradeEventDiscriminator[:]) {
+			continue
+		}
+		event, err := handleMoonshotTradeEvent(ag_binary.NewBorshDecoder(data[8:]))
+		if err != nil {
+			p.Log.Errorf("error processing Moonshot trade event: %s", err)
+			continue
+		}
+		return event
+	}
+	return nil
+}
+
+func handleMoonshotTradeEvent(decoder *ag_binary.Decoder) (*MoonshotTradeEvent, error) {
+	var event MoonshotTradeEvent
+	if err := decoder.Decode(&event); err != nil {
+		return nil, fmt.Errorf("error unmarshaling MoonshotTradeEvent: %s", err)
+	}
+	return &event, nil
+}
+
+func (p *Parser) getMoonshotPool(trade *MoonshotTradeInstructionWithMint) *MoonshotPool {
+	for i, outer := range p.txInfo.Message.Instructions {
+		instructions := []solana.CompiledInstruction{outer}
+		for _, inner := range p.getInnerInstructions(i) {
+			instructions = append(instructions, p.convertRPCToSolanaInstruction(inner))
+		}
+		for _, inst := range instructions {
+			if !p.isMoonshotTrade(inst) || !p.allAccountKeys[inst.Accounts[2]].Equals(trade.CurveAccount) {
+				continue
+			}
+			return &MoonshotPool{
+				CurveAccount:      p.allAccountKeys[inst.Accounts[2]],
+				CurveTokenAccount: p.allAccountKeys[inst.Accounts[3]],
+				DexFeeAccount:     p.allAccountKeys[inst.Accounts[4]],
+				HelioFeeAccount:   p.allAccountKeys[inst.Accounts[5]],
+				Mint:              p.allAccountKeys[inst.Accounts[6]],
+				ConfigAccount:     p.allAccountKeys[inst.Accounts[7]],
+				Allocation:        trade.Allocation,
+			}
+		}
+	}
+	return nil
+}
+
 // getTokenBalanceChanges calculates the balance change for a given token mint for the signer
 func (p *Parser) getTokenBalanceChanges(mint solana.PublicKey) (int64, error) {
 	if mint == NATIVE_SOL_MINT_PROGRAM_ID {
@@ -108,13 +220,13 @@ func (p *Parser) getTokenBalanceChanges(mint solana.PublicKey) (int64, error) {
 	}
 
 	// Get the signer's public key (assuming it's the first account in the transaction)
-	signer := p.txInfo.Message.AccountKeys[0]
+	signer := p.allAccountKeys[0]
 
 	var preAmount, postAmount int64
 	var balanceFound bool
 
 	for _, preBalance := range p.txMeta.PreTokenBalances {
-		if preBalance.Mint.Equals(mint) && preBalance.Owner.Equals(signer) {
+		if preBalance.Mint.Equals(mint) && preBalance.Owner != nil && preBalance.Owner.Equals(signer) {
 			preAmount, _ = strconv.ParseInt(preBalance.UiTokenAmount.Amount, 10, 64)
 			balanceFound = true
 			break
@@ -122,7 +234,7 @@ func (p *Parser) getTokenBalanceChanges(mint solana.PublicKey) (int64, error) {
 	}
 
 	for _, postBalance := range p.txMeta.PostTokenBalances {
-		if postBalance.Mint.Equals(mint) && postBalance.Owner.Equals(signer) {
+		if postBalance.Mint.Equals(mint) && postBalance.Owner != nil && postBalance.Owner.Equals(signer) {
 			postAmount, _ = strconv.ParseInt(postBalance.UiTokenAmount.Amount, 10, 64)
 			balanceFound = true
 			break
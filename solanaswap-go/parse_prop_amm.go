@@ -0,0 +1,241 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// propAmmLayout describes the swap instruction of a proprietary AMM. These programs
+// emit no events, so amounts and direction are read from the vault transfers.
+type propAmmLayout struct {
+	Name        SwapType
+	ProgramID   solana.PublicKey
+	MinAccounts int
+	Pool        int
+	VaultA      int
+	VaultB      int
+	// decodeArgs returns the requested input amount and the minimum output, ok is false for unknown instructions
+	decodeArgs func(data []byte) (amountIn uint64, minAmountOut uint64, ok bool)
+}
+
+var propAmmLayouts = []propAmmLayout{
+	{
+		// user, pair, pool token a, pool token b, user token a, user token b, token program, sysvar instructions
+		Name: SOLFI, ProgramID: SOLFI_PROGRAM_ID, MinAccounts: 6, Pool: 1, VaultA: 2, VaultB: 3,
+		decodeArgs: tagAmountArgs(7),
+	},
+	{
+		// trading pair, mint x, mint y, reserve x, reserve y, user x, user y, protocol fee, price feeds, user, token program
+		Name: OBRIC, ProgramID: OBRIC_V2_PROGRAM_ID, MinAccounts: 11, Pool: 0, VaultA: 3, VaultB: 4,
+		decodeArgs: func(data []byte) (uint64, uint64, bool) {
+			// swap(is_x_to_y: bool, input_amt: u64, min_output_amt: u64)
+			if len(data) < 25 || !bytes.Equal(data[:8], AnchorSwapDiscriminator[:]) {
+				return 0, 0, false
+			}
+			return binary.LittleEndian.Uint64(data[9:17]), binary.LittleEndian.Uint64(data[17:25]), true
+		},
+	},
+	{
+		// pair, vault info in, vault in, vault info out, vault out, user in, user out, user, token program, sysvar instructions
+		Name: ZEROFI, ProgramID: ZEROFI_PROGRAM_ID, MinAccounts: 8, Pool: 0, VaultA: 2, VaultB: 4,
+		decodeArgs: tagAmountArgs(6),
+	},
+	{
+		// user, pool, pool base vault, pool quote vault, user base, user quote, ... the instruction data is obfuscated
+		Name: HUMIDIFI, ProgramID: HUMIDIFI_PROGRAM_ID, MinAccounts: 6, Pool: 1, VaultA: 2, VaultB: 3,
+	},
+	{
+		// authority, amm, user transfer authority, source info, destination info, swap source, swap destination, pool mint, fee account, token program, oracles
+		Name: LIFINITY, ProgramID: LIFINITY_V2_PROGRAM_ID, MinAccounts: 10, Pool: 1, VaultA: 5, VaultB: 6,
+		decodeArgs: func(data []byte) (uint64, uint64, bool) {
+			// swap(amount_in: u64, minimum_amount_out: u64)
+			if len(data) < 24 || !bytes.Equal(data[:8], AnchorSwapDiscriminator[:]) {
+				return 0, 0, false
+			}
+			return binary.LittleEndian.Uint64(data[8:16]), binary.LittleEndian.Uint64(data[16:24]), true
+		},
+	},
+}
+
+// AnchorSwapDiscriminator is the instruction discriminator of an anchor "swap" instruction
+var AnchorSwapDiscriminator = [8]byte{248, 198, 158, 145, 225, 117, 135, 200}
+
+// tagAmountArgs decodes the native "tag, amount_in: u64, min_amount_out: u64" swap layout
+func tagAmountArgs(tag byte) func(data []byte) (uint64, uint64, bool) {
+	return func(data []byte) (uint64, uint64, bool) {
+		if len(data) < 17 || data[0] != tag {
+			return 0, 0, false
+		}
+		return binary.LittleEndian.Uint64(data[1:9]), binary.LittleEndian.Uint64(data[9:17]), true
+	}
+}
+
+// PropAmmSwap is a swap against a proprietary AMM, rebuilt from the pool vault transfers
+type PropAmmSwap struct {
+	AMM              SwapType
+	ProgramID        solana.PublicKey
+	Pool             solana.PublicKey
+	VaultA           solana.PublicKey
+	VaultB           solana.PublicKey
+	AToB             bool
+	User             string
+	AmountIn         uint64
+	MinAmountOut     uint64
+	TokenInMint      solana.PublicKey
+	TokenInAmount    uint64
+	TokenInDecimals  uint8
+	TokenOutMint     solana.PublicKey
+	TokenOutAmount   uint64
+	TokenOutDecimals uint8
+}
+
+func getPropAmmLayout(programID solana.PublicKey) *propAmmLayout {
+	for i := range propAmmLayouts {
+		if propAmmLayouts[i].ProgramID.Equals(programID) {
+			return &propAmmLayouts[i]
+		}
+	}
+	return nil
+}
+
+func isPropAmmProgram(programID solana.PublicKey) bool {
+	return getPropAmmLayout(programID) != nil
+}
+
+// processPropAmmSwaps processes the prop AMM swaps of an outer instruction, including swaps made through CPI
+func (p *Parser) processPropAmmSwaps(instructionIndex int) []SwapData {
+	var swaps []SwapData
+
+	innerInstructions := p.getInnerInstructions(instructionIndex)
+
+	outerInstruction := p.txInfo.Message.Instructions[instructionIndex]
+	if swap := p.parsePropAmmSwap(outerInstruction, innerInstructions); swap != nil {
+		swaps = append(swaps, SwapData{Type: swap.AMM, Data: swap})
+	}
+
+	ends := p.cpiEnds(instructionIndex)
+	for j, innerInstruction := range innerInstructions {
+		if swap := p.parsePropAmmSwap(p.convertRPCToSolanaInstruction(innerInstruction), innerInstructions[j+1:ends[j]]); swap != nil {
+			swaps = append(swaps, SwapData{Type: swap.AMM, Data: swap})
+		}
+	}
+
+	return swaps
+}
+
+// parsePropAmmSwap matches the vault transfers among the inner instructions the swap executed
+func (p *Parser) parsePropAmmSwap(inst solana.CompiledInstruction, following []rpc.CompiledInstruction) *PropAmmSwap {
+	layout := getPropAmmLayout(p.allAccountKeys[inst.ProgramIDIndex])
+	if layout == nil || len(inst.Accounts) < layout.MinAccounts {
+		return nil
+	}
+
+	swap := &PropAmmSwap{
+		AMM:       layout.Name,
+		ProgramID: layout.ProgramID,
+		Pool:      p.allAccountKeys[inst.Accounts[layout.Pool]],
+		VaultA:    p.allAccountKeys[inst.Accounts[layout.VaultA]],
+		VaultB:    p.allAccountKeys[inst.Accounts[layout.VaultB]],
+	}
+	if layout.decodeArgs != nil {
+		swap.AmountIn, swap.MinAmountOut, _ = layout.decodeArgs(inst.Data)
+	}
+
+	vaults := map[string]bool{swap.VaultA.String(): true, swap.VaultB.String(): true}
+	inFound, outFound := false, false
+	for _, following := range following {
+		transfer := p.parseTokenMovement(p.convertRPCToSolanaInstruction(following))
+		if transfer == nil {
+			continue
+		}
+		switch {
+		case !inFound && vaults[transfer.destination]:
+			swap.TokenInMint = solana.MustPublicKeyFromBase58(transfer.mint)
+			swap.TokenInAmount = transfer.amount
+			swap.TokenInDecimals = transfer.decimals
+			swap.AToB = transfer.destination == swap.VaultA.String()
+			swap.User = transfer.user
+			inFound = true
+		case !outFound && vaults[transfer.source]:
+			swap.TokenOutMint = solana.MustPublicKeyFromBase58(transfer.mint)
+			swap.TokenOutAmount = transfer.amount
+			swap.TokenOutDecimals = transfer.decimals
+			outFound = true
+		}
+		if inFound && outFound {
+			return swap
+		}
+	}
+
+	return nil
+}
+
+// tokenMovement is a Transfer or TransferChecked instruction, user is the transfer authority
+type tokenMovement struct {
+	user        string
+	source      string
+	destination string
+	mint        string
+	amount      uint64
+	decimals    uint8
+}
+
+func (p *Parser) parseTokenMovement(inst solana.CompiledInstruction) *tokenMovement {
+	switch {
+	case p.isTransferCheck(inst):
+		transfer := p.processTransferCheck(inst)
+		amount, err := strconv.ParseUint(transfer.Info.TokenAmount.Amount, 10, 64)
+		if err != nil {
+			return nil
+		}
+		return &tokenMovement{
+			user:        transfer.Info.Authority,
+			source:      transfer.Info.Source,
+			destination: transfer.Info.Destination,
+			mint:        transfer.Info.Mint,
+			amount:      amount,
+			decimals:    transfer.Info.TokenAmount.Decimals,
+		}
+	case p.isTokenTransfer(inst):
+		transfer := p.processTokenTransfer(inst)
+		if transfer == nil || transfer.Mint == "Unknown" {
+			return nil
+		}
+		return &tokenMovement{
+			user:        transfer.Info.Authority,
+			source:      transfer.Info.Source,
+			destination: transfer.Info.Destination,
+			mint:        transfer.Mint,
+			amount:      transfer.Info.Amount,
+			decimals:    transfer.Decimals,
+		}
+	}
+	return nil
+}
+
+func (s *PropAmmSwap) swapLeg() SwapLeg {
+	return SwapLeg{
+		AMM:              string(s.AMM),
+		ProgramID:        s.ProgramID,
+		Pool:             s.Pool,
+		TokenInMint:      s.TokenInMint,
+		TokenInAmount:    s.TokenInAmount,
+		TokenInDecimals:  s.TokenInDecimals,
+		TokenOutMint:     s.TokenOutMint,
+		TokenOutAmount:   s.TokenOutAmount,
+		TokenOutDecimals: s.TokenOutDecimals,
+		Data:             s,
+	}
+}
+
+func (s *PropAmmSwap) swapFees() []SwapFee {
+	return nil
+}
+
+func (s *PropAmmSwap) timestamp() int64 {
+	return 0
+}
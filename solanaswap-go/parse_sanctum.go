@@ -0,0 +1,456 @@
+package solanaswapgo
+
+import (
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// spl stake pool instruction tags
+const (
+	STAKE_POOL_DEPOSIT_STAKE_INSTRUCTION  = 9
+	STAKE_POOL_WITHDRAW_STAKE_INSTRUCTION = 10
+	STAKE_POOL_DEPOSIT_SOL_INSTRUCTION    = 14
+	STAKE_POOL_WITHDRAW_SOL_INSTRUCTION   = 16
+)
+
+// sanctum infinity instruction tags
+const (
+	INFINITY_SWAP_EXACT_IN_INSTRUCTION  = 1
+	INFINITY_SWAP_EXACT_OUT_INSTRUCTION = 2
+)
+
+const (
+	tokenMintToInstruction        = 7
+	tokenMintToCheckedInstruction = 14
+	stakeWithdrawInstruction      = 4
+)
+
+// StakePoolSwap is a DepositSol or WithdrawSol against an SPL stake pool, amounts are native
+type StakePoolSwap struct {
+	Instruction        string
+	ProgramID          solana.PublicKey
+	StakePool          solana.PublicKey
+	LstMint            solana.PublicKey
+	LstDecimals        uint8
+	User               solana.PublicKey
+	SolAmount          uint64
+	LstAmount          uint64
+	ManagerFee         uint64
+	ManagerFeeAccount  solana.PublicKey
+	ReferralFee        uint64
+	ReferralFeeAccount solana.PublicKey
+}
+
+// InfinitySwap is an LST to LST swap against the Sanctum Infinity pool
+type InfinitySwap struct {
+	Instruction            string
+	Pool                   solana.PublicKey
+	User                   solana.PublicKey
+	TokenInMint            solana.PublicKey
+	TokenInAmount          uint64
+	TokenInDecimals        uint8
+	TokenOutMint           solana.PublicKey
+	TokenOutAmount         uint64
+	TokenOutDecimals       uint8
+	ProtocolFee            uint64
+	ProtocolFeeAccumulator solana.PublicKey
+}
+
+// SanctumRouterSwap is an LST to LST swap routed through a stake account, a WithdrawStake
+// from the source pool followed by a DepositStake into the destination pool
+type SanctumRouterSwap struct {
+	User                 solana.PublicKey
+	WithdrawPool         solana.PublicKey
+	DepositPool          solana.PublicKey
+	TokenInMint          solana.PublicKey
+	TokenInAmount        uint64
+	TokenInDecimals      uint8
+	TokenOutMint         solana.PublicKey
+	TokenOutAmount       uint64
+	TokenOutDecimals     uint8
+	WithdrawalFee        uint64
+	WithdrawalFeeAccount solana.PublicKey
+	DepositFee           uint64
+	DepositFeeAccount    solana.PublicKey
+	ReferralFee          uint64
+	ReferralFeeAccount   solana.PublicKey
+}
+
+var stakePoolPrograms = []solana.PublicKey{
+	SPL_STAKE_POOL_PROGRAM_ID,
+	SANCTUM_SPL_STAKE_POOL_PROGRAM_ID,
+	SANCTUM_MULTI_STAKE_POOL_PROGRAM_ID,
+}
+
+func isStakePoolProgram(programID solana.PublicKey) bool {
+	for _, id := range stakePoolPrograms {
+		if id.Equals(programID) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSanctumProgram(programID solana.PublicKey) bool {
+	return isStakePoolProgram(programID) ||
+		programID.Equals(SANCTUM_INFINITY_PROGRAM_ID) ||
+		programID.Equals(SANCTUM_ROUTER_PROGRAM_ID)
+}
+
+// processSanctumSwaps processes the stake pool and Infinity swaps of an outer instruction, including CPI from the Sanctum router
+func (p *Parser) processSanctumSwaps(instructionIndex int) []SwapData {
+	var swaps []SwapData
+
+	innerInstructions := p.getInnerInstructions(instructionIndex)
+
+	parse := func(inst solana.CompiledInstruction, following []rpc.CompiledInstruction) {
+		progID := p.allAccountKeys[inst.ProgramIDIndex]
+		switch {
+		case isStakePoolProgram(progID):
+			if swap := p.parseStakePoolSwap(inst, following); swap != nil {
+				swaps = append(swaps, SwapData{Type: SANCTUM, Data: swap})
+			}
+		case progID.Equals(SANCTUM_INFINITY_PROGRAM_ID):
+			if swap := p.parseInfinitySwap(inst, following); swap != nil {
+				swaps = append(swaps, SwapData{Type: SANCTUM_INFINITY, Data: swap})
+			}
+		}
+	}
+
+	parse(p.txInfo.Message.Instructions[instructionIndex], innerInstructions)
+	ends := p.cpiEnds(instructionIndex)
+	for j, innerInstruction := range innerInstructions {
+		parse(p.convertRPCToSolanaInstruction(innerInstruction), innerInstructions[j+1:ends[j]])
+	}
+
+	if len(swaps) == 0 {
+		if swap := p.parseSanctumSwapViaStake(innerInstructions); swap != nil {
+			swaps = append(swaps, SwapData{Type: SANCTUM, Data: swap})
+		}
+	}
+
+	return swaps
+}
+
+// parseSanctumSwapViaStake pairs the WithdrawStake and DepositStake made by the router
+func (p *Parser) parseSanctumSwapViaStake(innerInstructions []rpc.CompiledInstruction) *SanctumRouterSwap {
+	var swap *SanctumRouterSwap
+	for j, innerInstruction := range innerInstructions {
+		inst := p.convertRPCToSolanaInstruction(innerInstruction)
+		if !isStakePoolProgram(p.allAccountKeys[inst.ProgramIDIndex]) || len(inst.Data) == 0 {
+			continue
+		}
+
+		switch {
+		case inst.Data[0] == STAKE_POOL_WITHDRAW_STAKE_INSTRUCTION && swap == nil:
+			// stake pool, validator list, withdraw authority, stake to split, stake to receive, user stake authority,
+			// user transfer authority, pool tokens from, manager fee account, pool mint, ...
+			if len(inst.Data) < 9 || len(inst.Accounts) < 10 {
+				continue
+			}
+			swap = &SanctumRouterSwap{
+				User:                 p.allAccountKeys[inst.Accounts[6]],
+				WithdrawPool:         p.allAccountKeys[inst.Accounts[0]],
+				TokenInMint:          p.allAccountKeys[inst.Accounts[9]],
+				TokenInAmount:        binary.LittleEndian.Uint64(inst.Data[1:9]),
+				WithdrawalFeeAccount: p.allAccountKeys[inst.Accounts[8]],
+			}
+			swap.TokenInDecimals = p.splDecimalsMap[swap.TokenInMint.String()]
+			poolTokensFrom := p.allAccountKeys[inst.Accounts[7]].String()
+			for _, following := range innerInstructions[j+1:] {
+				transfer := p.parseTokenMovement(p.convertRPCToSolanaInstruction(following))
+				if transfer != nil && transfer.source == poolTokensFrom && transfer.destination == swap.WithdrawalFeeAccount.String() {
+					swap.WithdrawalFee = transfer.amount
+					break
+				}
+			}
+
+		case inst.Data[0] == STAKE_POOL_DEPOSIT_STAKE_INSTRUCTION && swap != nil:
+			// stake pool, validator list, deposit authority, withdraw authority, stake to join, validator stake,
+			// reserve stake, pool tokens to, manager fee account, referrer pool tokens, pool mint, ...
+			if len(inst.Accounts) < 11 {
+				continue
+			}
+			swap.DepositPool = p.allAccountKeys[inst.Accounts[0]]
+			swap.DepositFeeAccount = p.allAccountKeys[inst.Accounts[8]]
+			swap.ReferralFeeAccount = p.allAccountKeys[inst.Accounts[9]]
+			swap.TokenOutMint = p.allAccountKeys[inst.Accounts[10]]
+			swap.TokenOutDecimals = p.splDecimalsMap[swap.TokenOutMint.String()]
+			poolTokensTo := p.allAccountKeys[inst.Accounts[7]]
+			for _, following := range innerInstructions[j+1:] {
+				mint, destination, amount, ok := p.parseMintTo(p.convertRPCToSolanaInstruction(following))
+				if !ok || !mint.Equals(swap.TokenOutMint) {
+					continue
+				}
+				switch {
+				case destination.Equals(poolTokensTo) && swap.TokenOutAmount == 0:
+					swap.TokenOutAmount = amount
+				case destination.Equals(swap.DepositFeeAccount) && swap.DepositFee == 0:
+					swap.DepositFee = amount
+				case destination.Equals(swap.ReferralFeeAccount) && swap.ReferralFee == 0:
+					swap.ReferralFee = amount
+				}
+			}
+			if swap.TokenOutAmount == 0 {
+				return nil
+			}
+			return swap
+		}
+	}
+	return nil
+}
+
+func (p *Parser) parseStakePoolSwap(inst solana.CompiledInstruction, following []rpc.CompiledInstruction) *StakePoolSwap {
+	if len(inst.Data) < 9 {
+		return nil
+	}
+
+	switch inst.Data[0] {
+	case STAKE_POOL_DEPOSIT_SOL_INSTRUCTION:
+		// stake pool, withdraw authority, reserve stake, lamports from, user pool tokens, manager fee account, referrer pool tokens, pool mint, ...
+		if len(inst.Accounts) < 10 {
+			return nil
+		}
+		swap := &StakePoolSwap{
+			Instruction:        "DepositSol",
+			ProgramID:          p.allAccountKeys[inst.ProgramIDIndex],
+			StakePool:          p.allAccountKeys[inst.Accounts[0]],
+			User:               p.allAccountKeys[inst.Accounts[3]],
+			SolAmount:          binary.LittleEndian.Uint64(inst.Data[1:9]),
+			ManagerFeeAccount:  p.allAccountKeys[inst.Accounts[5]],
+			ReferralFeeAccount: p.allAccountKeys[inst.Accounts[6]],
+			LstMint:            p.allAccountKeys[inst.Accounts[7]],
+		}
+		swap.LstDecimals = p.splDecimalsMap[swap.LstMint.String()]
+		userPoolTokens := p.allAccountKeys[inst.Accounts[4]]
+
+		// the pool mints the user share first, then the manager and referral fees
+		for _, following := range following {
+			mint, destination, amount, ok := p.parseMintTo(p.convertRPCToSolanaInstruction(following))
+			if !ok || !mint.Equals(swap.LstMint) {
+				continue
+			}
+			switch {
+			case destination.Equals(userPoolTokens) && swap.LstAmount == 0:
+				swap.LstAmount = amount
+			case destination.Equals(swap.ManagerFeeAccount) && swap.ManagerFee == 0:
+				swap.ManagerFee = amount
+			case destination.Equals(swap.ReferralFeeAccount) && swap.ReferralFee == 0:
+				swap.ReferralFee = amount
+			}
+		}
+		if swap.LstAmount == 0 {
+			return nil
+		}
+		return swap
+
+	case STAKE_POOL_WITHDRAW_SOL_INSTRUCTION:
+		// stake pool, withdraw authority, user transfer authority, pool tokens from, reserve stake, lamports to, manager fee account, pool mint, ...
+		if len(inst.Accounts) < 12 {
+			return nil
+		}
+		swap := &StakePoolSwap{
+			Instruction:       "WithdrawSol",
+			ProgramID:         p.allAccountKeys[inst.ProgramIDIndex],
+			StakePool:         p.allAccountKeys[inst.Accounts[0]],
+			User:              p.allAccountKeys[inst.Accounts[2]],
+			LstAmount:         binary.LittleEndian.Uint64(inst.Data[1:9]),
+			ManagerFeeAccount: p.allAccountKeys[inst.Accounts[6]],
+			LstMint:           p.allAccountKeys[inst.Accounts[7]],
+		}
+		swap.LstDecimals = p.splDecimalsMap[swap.LstMint.String()]
+		poolTokensFrom := p.allAccountKeys[inst.Accounts[3]].String()
+		reserveStake := p.allAccountKeys[inst.Accounts[4]]
+		lamportsTo := p.allAccountKeys[inst.Accounts[5]]
+
+		for _, following := range following {
+			instr := p.convertRPCToSolanaInstruction(following)
+			if transfer := p.parseTokenMovement(instr); transfer != nil {
+				if transfer.source == poolTokensFrom && transfer.destination == swap.ManagerFeeAccount.String() {
+					swap.ManagerFee = transfer.amount
+				}
+				continue
+			}
+			if from, to, lamports, ok := p.parseStakeWithdraw(instr); ok && from.Equals(reserveStake) && to.Equals(lamportsTo) {
+				swap.SolAmount = lamports
+			}
+		}
+		if swap.SolAmount == 0 {
+			return nil
+		}
+		return swap
+	}
+
+	return nil
+}
+
+func (p *Parser) parseInfinitySwap(inst solana.CompiledInstruction, following []rpc.CompiledInstruction) *InfinitySwap {
+	if len(inst.Data) == 0 || len(inst.Accounts) < 12 {
+		return nil
+	}
+
+	swap := &InfinitySwap{}
+	switch inst.Data[0] {
+	case INFINITY_SWAP_EXACT_IN_INSTRUCTION:
+		swap.Instruction = "SwapExactIn"
+	case INFINITY_SWAP_EXACT_OUT_INSTRUCTION:
+		swap.Instruction = "SwapExactOut"
+	default:
+		return nil
+	}
+
+	// signer, src lst mint, dst lst mint, src lst account, dst lst account, protocol fee accumulator,
+	// src token program, dst token program, pool state, lst state list, src pool reserves, dst pool reserves, ...
+	swap.User = p.allAccountKeys[inst.Accounts[0]]
+	swap.TokenInMint = p.allAccountKeys[inst.Accounts[1]]
+	swap.TokenOutMint = p.allAccountKeys[inst.Accounts[2]]
+	swap.ProtocolFeeAccumulator = p.allAccountKeys[inst.Accounts[5]]
+	swap.Pool = p.allAccountKeys[inst.Accounts[8]]
+	swap.TokenInDecimals = p.splDecimalsMap[swap.TokenInMint.String()]
+	swap.TokenOutDecimals = p.splDecimalsMap[swap.TokenOutMint.String()]
+
+	srcAccount := p.allAccountKeys[inst.Accounts[3]].String()
+	dstAccount := p.allAccountKeys[inst.Accounts[4]].String()
+	srcReserves := p.allAccountKeys[inst.Accounts[10]].String()
+	dstReserves := p.allAccountKeys[inst.Accounts[11]].String()
+
+	for _, following := range following {
+		transfer := p.parseTokenMovement(p.convertRPCToSolanaInstruction(following))
+		if transfer == nil {
+			continue
+		}
+		switch {
+		case transfer.source == srcAccount && transfer.destination == srcReserves && swap.TokenInAmount == 0:
+			swap.TokenInAmount = transfer.amount
+		case transfer.source == dstReserves && transfer.destination == dstAccount && swap.TokenOutAmount == 0:
+			swap.TokenOutAmount = transfer.amount
+		case transfer.source == dstReserves && transfer.destination == swap.ProtocolFeeAccumulator.String() && swap.ProtocolFee == 0:
+			swap.ProtocolFee = transfer.amount
+		}
+	}
+	if swap.TokenInAmount == 0 || swap.TokenOutAmount == 0 {
+		return nil
+	}
+	return swap
+}
+
+// parseMintTo decodes a token MintTo / MintToChecked instruction
+func (p *Parser) parseMintTo(inst solana.CompiledInstruction) (mint solana.PublicKey, destination solana.PublicKey, amount uint64, ok bool) {
+	progID := p.allAccountKeys[inst.ProgramIDIndex]
+	if !progID.Equals(solana.TokenProgramID) && !progID.Equals(solana.Token2022ProgramID) {
+		return
+	}
+	if len(inst.Data) < 9 || len(inst.Accounts) < 3 ||
+		(inst.Data[0] != tokenMintToInstruction && inst.Data[0] != tokenMintToCheckedInstruction) {
+		return
+	}
+	return p.allAccountKeys[inst.Accounts[0]], p.allAccountKeys[inst.Accounts[1]], binary.LittleEndian.Uint64(inst.Data[1:9]), true
+}
+
+// parseStakeWithdraw decodes a stake program Withdraw instruction
+func (p *Parser) parseStakeWithdraw(inst solana.CompiledInstruction) (from solana.PublicKey, to solana.PublicKey, lamports uint64, ok bool) {
+	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(solana.StakeProgramID) {
+		return
+	}
+	if len(inst.Data) < 12 || len(inst.Accounts) < 2 || binary.LittleEndian.Uint32(inst.Data[:4]) != stakeWithdrawInstruction {
+		return
+	}
+	return p.allAccountKeys[inst.Accounts[0]], p.allAccountKeys[inst.Accounts[1]], binary.LittleEndian.Uint64(inst.Data[4:12]), true
+}
+
+func (s *StakePoolSwap) swapLeg() SwapLeg {
+	leg := SwapLeg{
+		AMM:       string(SANCTUM),
+		ProgramID: s.ProgramID,
+		Pool:      s.StakePool,
+		Data:      s,
+	}
+	if s.Instruction == "DepositSol" {
+		leg.TokenInMint, leg.TokenInAmount, leg.TokenInDecimals = NATIVE_SOL_MINT_PROGRAM_ID, s.SolAmount, 9
+		leg.TokenOutMint, leg.TokenOutAmount, leg.TokenOutDecimals = s.LstMint, s.LstAmount, s.LstDecimals
+	} else {
+		leg.TokenInMint, leg.TokenInAmount, leg.TokenInDecimals = s.LstMint, s.LstAmount, s.LstDecimals
+		leg.TokenOutMint, leg.TokenOutAmount, leg.TokenOutDecimals = NATIVE_SOL_MINT_PROGRAM_ID, s.SolAmount, 9
+	}
+	return leg
+}
+
+func (s *StakePoolSwap) swapFees() []SwapFee {
+	var fees []SwapFee
+	feeType := "deposit"
+	if s.Instruction == "WithdrawSol" {
+		feeType = "withdrawal"
+	}
+	if s.ManagerFee > 0 {
+		fees = append(fees, SwapFee{Type: feeType, Mint: s.LstMint, Amount: s.ManagerFee, Recipient: s.ManagerFeeAccount})
+	}
+	if s.ReferralFee > 0 {
+		fees = append(fees, SwapFee{Type: "referrer", Mint: s.LstMint, Amount: s.ReferralFee, Recipient: s.ReferralFeeAccount})
+	}
+	return fees
+}
+
+func (s *StakePoolSwap) timestamp() int64 {
+	return 0
+}
+
+func (s *InfinitySwap) swapLeg() SwapLeg {
+	return SwapLeg{
+		AMM:              string(SANCTUM_INFINITY),
+		ProgramID:        SANCTUM_INFINITY_PROGRAM_ID,
+		Pool:             s.Pool,
+		TokenInMint:      s.TokenInMint,
+		TokenInAmount:    s.TokenInAmount,
+		TokenInDecimals:  s.TokenInDecimals,
+		TokenOutMint:     s.TokenOutMint,
+		TokenOutAmount:   s.TokenOutAmount,
+		TokenOutDecimals: s.TokenOutDecimals,
+		Data:             s,
+	}
+}
+
+func (s *InfinitySwap) swapFees() []SwapFee {
+	if s.ProtocolFee == 0 {
+		return nil
+	}
+	return []SwapFee{{Type: "protocol", Mint: s.TokenOutMint, Amount: s.ProtocolFee, Recipient: s.ProtocolFeeAccumulator}}
+}
+
+func (s *InfinitySwap) timestamp() int64 {
+	return 0
+}
+
+func (s *SanctumRouterSwap) swapLeg() SwapLeg {
+	return SwapLeg{
+		AMM:              string(SANCTUM),
+		ProgramID:        SANCTUM_ROUTER_PROGRAM_ID,
+		Pool:             s.DepositPool,
+		TokenInMint:      s.TokenInMint,
+		TokenInAmount:    s.TokenInAmount,
+		TokenInDecimals:  s.TokenInDecimals,
+		TokenOutMint:     s.TokenOutMint,
+		TokenOutAmount:   s.TokenOutAmount,
+		TokenOutDecimals: s.TokenOutDecimals,
+		Data:             s,
+	}
+}
+
+func (s *SanctumRouterSwap) swapFees() []SwapFee {
+	var fees []SwapFee
+	if s.WithdrawalFee > 0 {
+		fees = append(fees, SwapFee{Type: "withdrawal", Mint: s.TokenInMint, Amount: s.WithdrawalFee, Recipient: s.WithdrawalFeeAccount})
+	}
+	if s.DepositFee > 0 {
+		fees = append(fees, SwapFee{Type: "deposit", Mint: s.TokenOutMint, Amount: s.DepositFee, Recipient: s.DepositFeeAccount})
+	}
+	if s.ReferralFee > 0 {
+		fees = append(fees, SwapFee{Type: "referrer", Mint: s.TokenOutMint, Amount: s.ReferralFee, Recipient: s.ReferralFeeAccount})
+	}
+	return fees
+}
+
+func (s *SanctumRouterSwap) timestamp() int64 {
+	return 0
+}
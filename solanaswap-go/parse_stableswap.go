@@ -0,0 +1,210 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// stableSwapLayout describes the swap instruction of a stableswap program. Amounts
+// follow the amplified invariant on chain, so they are read from the user transfers.
+type stableSwapLayout struct {
+	Name            SwapType
+	ProgramID       solana.PublicKey
+	MinAccounts     int
+	Pool            int
+	UserSource      int // negative values count from the last account
+	UserDestination int // negative values count from the last account
+	AdminFee        int // -1 when the admin fee stays in the pool
+	decodeArgs      func(data []byte) (amountIn uint64, minAmountOut uint64, ok bool)
+}
+
+var stableSwapLayouts = []stableSwapLayout{
+	{
+		// swap, swap authority, user authority, user source, pool source, pool destination, user destination, admin destination, token program
+		Name: SABER, ProgramID: SABER_STABLE_SWAP_PROGRAM_ID, MinAccounts: 8, Pool: 0, UserSource: 3, UserDestination: 6, AdminFee: 7,
+		decodeArgs: tagAmountArgs(1),
+	},
+	{
+		// swap, token program, pool authority, user transfer authority, pool token accounts..., user source, user destination
+		Name: MERCURIAL, ProgramID: MERCURIAL_PROGRAM_ID, MinAccounts: 8, Pool: 0, UserSource: -2, UserDestination: -1, AdminFee: -1,
+		decodeArgs: tagAmountArgs(4),
+	},
+	{
+		// user, user token in, user token out, vault token in, vault token out, beneficiary token out, pool, withdraw authority, vault, ...
+		Name: STABBLE, ProgramID: STABBLE_STABLE_SWAP_PROGRAM_ID, MinAccounts: 7, Pool: 6, UserSource: 1, UserDestination: 2, AdminFee: 5,
+		decodeArgs: stabbleSwapArgs,
+	},
+	{
+		Name: STABBLE, ProgramID: STABBLE_WEIGHTED_SWAP_PROGRAM_ID, MinAccounts: 7, Pool: 6, UserSource: 1, UserDestination: 2, AdminFee: 5,
+		decodeArgs: stabbleSwapArgs,
+	},
+}
+
+// stabbleSwapArgs decodes swap(amount_in: Option<u64>, minimum_amount_out: u64)
+func stabbleSwapArgs(data []byte) (uint64, uint64, bool) {
+	if len(data) < 9 || !bytes.Equal(data[:8], AnchorSwapDiscriminator[:]) {
+		return 0, 0, false
+	}
+	var amountIn uint64
+	offset := 9
+	if data[8] == 1 {
+		if len(data) < 17 {
+			return 0, 0, false
+		}
+		amountIn = binary.LittleEndian.Uint64(data[9:17])
+		offset = 17
+	}
+	if len(data) < offset+8 {
+		return 0, 0, false
+	}
+	return amountIn, binary.LittleEndian.Uint64(data[offset : offset+8]), true
+}
+
+// StableSwap is a swap against a stableswap pool, rebuilt from the user transfers
+type StableSwap struct {
+	AMM              SwapType
+	ProgramID        solana.PublicKey
+	Pool             solana.PublicKey
+	User             string
+	AmountIn         uint64
+	MinAmountOut     uint64
+	TokenInMint      solana.PublicKey
+	TokenInAmount    uint64
+	TokenInDecimals  uint8
+	TokenOutMint     solana.PublicKey
+	TokenOutAmount   uint64
+	TokenOutDecimals uint8
+	ReserveIn        solana.PublicKey
+	ReserveOut       solana.PublicKey
+	AdminFee         uint64
+	AdminFeeAccount  solana.PublicKey
+}
+
+func getStableSwapLayout(programID solana.PublicKey) *stableSwapLayout {
+	for i := range stableSwapLayouts {
+		if stableSwapLayouts[i].ProgramID.Equals(programID) {
+			return &stableSwapLayouts[i]
+		}
+	}
+	return nil
+}
+
+func isStableSwapProgram(programID solana.PublicKey) bool {
+	return getStableSwapLayout(programID) != nil
+}
+
+// processStableSwaps processes the stableswap swaps of an outer instruction, including swaps made through CPI
+func (p *Parser) processStableSwaps(instructionIndex int) []SwapData {
+	var swaps []SwapData
+
+	innerInstructions := p.getInnerInstructions(instructionIndex)
+
+	outerInstruction := p.txInfo.Message.Instructions[instructionIndex]
+	if swap := p.parseStableSwap(outerInstruction, innerInstructions); swap != nil {
+		swaps = append(swaps, SwapData{Type: swap.AMM, Data: swap})
+	}
+
+	ends := p.cpiEnds(instructionIndex)
+	for j, innerInstruction := range innerInstructions {
+		if swap := p.parseStableSwap(p.convertRPCToSolanaInstruction(innerInstruction), innerInstructions[j+1:ends[j]]); swap != nil {
+			swaps = append(swaps, SwapData{Type: swap.AMM, Data: swap})
+		}
+	}
+
+	return swaps
+}
+
+// parseStableSwap matches the user transfers and the admin fee among the inner instructions the swap executed
+func (p *Parser) parseStableSwap(inst solana.CompiledInstruction, following []rpc.CompiledInstruction) *StableSwap {
+	layout := getStableSwapLayout(p.allAccountKeys[inst.ProgramIDIndex])
+	if layout == nil || len(inst.Accounts) < layout.MinAccounts {
+		return nil
+	}
+
+	var ok bool
+	swap := &StableSwap{
+		AMM:       layout.Name,
+		ProgramID: layout.ProgramID,
+		Pool:      p.allAccountKeys[inst.Accounts[layout.Pool]],
+	}
+	if swap.AmountIn, swap.MinAmountOut, ok = layout.decodeArgs(inst.Data); !ok {
+		return nil
+	}
+
+	account := func(index int) string {
+		if index < 0 {
+			index += len(inst.Accounts)
+		}
+		return p.allAccountKeys[inst.Accounts[index]].String()
+	}
+	userSource := account(layout.UserSource)
+	userDestination := account(layout.UserDestination)
+	adminFeeAccount := ""
+	if layout.AdminFee >= 0 {
+		swap.AdminFeeAccount = p.allAccountKeys[inst.Accounts[layout.AdminFee]]
+		adminFeeAccount = swap.AdminFeeAccount.String()
+	}
+
+	inFound, outFound := false, false
+	for _, following := range following {
+		transfer := p.parseTokenMovement(p.convertRPCToSolanaInstruction(following))
+		if transfer == nil {
+			continue
+		}
+		switch {
+		case !inFound && transfer.source == userSource:
+			swap.TokenInMint = solana.MustPublicKeyFromBase58(transfer.mint)
+			swap.TokenInAmount = transfer.amount
+			swap.TokenInDecimals = transfer.decimals
+			swap.ReserveIn = solana.MustPublicKeyFromBase58(transfer.destination)
+			swap.User = transfer.user
+			inFound = true
+		case !outFound && transfer.destination == userDestination:
+			swap.TokenOutMint = solana.MustPublicKeyFromBase58(transfer.mint)
+			swap.TokenOutAmount = transfer.amount
+			swap.TokenOutDecimals = transfer.decimals
+			swap.ReserveOut = solana.MustPublicKeyFromBase58(transfer.source)
+			outFound = true
+		case adminFeeAccount != "" && swap.AdminFee == 0 && transfer.destination == adminFeeAccount:
+			swap.AdminFee = transfer.amount
+		}
+		// the admin fee is paid after the user transfer on Saber and Stabble
+		if inFound && outFound && (adminFeeAccount == "" || swap.AdminFee > 0) {
+			break
+		}
+	}
+
+	if !inFound || !outFound {
+		return nil
+	}
+	return swap
+}
+
+func (s *StableSwap) swapLeg() SwapLeg {
+	return SwapLeg{
+		AMM:              string(s.AMM),
+		ProgramID:        s.ProgramID,
+		Pool:             s.Pool,
+		TokenInMint:      s.TokenInMint,
+		TokenInAmount:    s.TokenInAmount,
+		TokenInDecimals:  s.TokenInDecimals,
+		TokenOutMint:     s.TokenOutMint,
+		TokenOutAmount:   s.TokenOutAmount,
+		TokenOutDecimals: s.TokenOutDecimals,
+		Data:             s,
+	}
+}
+
+func (s *StableSwap) swapFees() []SwapFee {
+	if s.AdminFee == 0 {
+		return nil
+	}
+	return []SwapFee{{Type: "admin", Mint: s.TokenOutMint, Amount: s.AdminFee, Recipient: s.AdminFeeAccount}}
+}
+
+func (s *StableSwap) timestamp() int64 {
+	return 0
+}
@@ -53,7 +53,8 @@ func (p *Parser) processMeteoraSwaps(instructionIndex int) []SwapData {
 	return swaps
 }
 
-func (p *Parser) processAxionSwaps(instructionIndex int) []SwapData {
+// processTransferSwaps reads the swaps of a transfer-based catalog program from its token and SOL transfers
+func (p *Parser) processTransferSwaps(instructionIndex int, swapType SwapType) []SwapData {
 	var swaps []SwapData
 	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
 		if innerInstructionSet.Index == uint16(instructionIndex) {
@@ -62,17 +63,17 @@ func (p *Parser) processAxionSwaps(instructionIndex int) []SwapData {
 				case p.isTransferCheck(p.convertRPCToSolanaInstruction(innerInstruction)):
 					transfer := p.processTransferCheck(p.convertRPCToSolanaInstruction(innerInstruction))
 					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: AXION, Data: transfer})
+						swaps = append(swaps, SwapData{Type: swapType, Data: transfer})
 					}
 				case p.isTokenTransfer(p.convertRPCToSolanaInstruction(innerInstruction)):
 					transfer := p.processTokenTransfer(p.convertRPCToSolanaInstruction(innerInstruction))
 					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: AXION, Data: transfer})
+						swaps = append(swaps, SwapData{Type: swapType, Data: transfer})
 					}
 				case p.isSystemTransfer(p.convertRPCToSolanaInstruction(innerInstruction)):
 					transfer := p.processSystemTransfer(p.convertRPCToSolanaInstruction(innerInstruction))
 					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: AXION, Data: transfer})
+						swaps = append(swaps, SwapData{Type: swapType, Data: transfer})
 					}
 				}
 			}
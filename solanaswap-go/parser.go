@@ -18,13 +18,21 @@ const (
 	PROTOCOL_METEORA  = "meteora"
 	PROTOCOL_PUMPFUN  = "pumpfun"
 	PROTOCOL_PUMPSWAP = "pumpswap"
+	PROTOCOL_MOONSHOT = "moonshot"
+	PROTOCOL_PHOENIX  = "phoenix"
+	PROTOCOL_OPENBOOK = "openbook"
+	PROTOCOL_PROP_AMM = "propamm"
+	PROTOCOL_SANCTUM  = "sanctum"
+	PROTOCOL_STABLE   = "stableswap"
 )
 
 type TokenTransfer struct {
-	user     string
-	mint     string
-	amount   uint64
-	decimals uint8
+	user        string
+	source      string
+	destination string
+	mint        string
+	amount      uint64
+	decimals    uint8
 }
 
 type Parser struct {
@@ -36,6 +44,7 @@ type Parser struct {
 	splDecimalsMap  map[string]uint8
 	SwapType        SwapType
 	Log             *logrus.Logger
+	moonshotData    [][][]byte // "Program data:" payloads of the Moonshot invocations, read on first use
 }
 
 func NewTransactionParser(tx *rpc.GetTransactionResult) (*Parser, error) {
@@ -116,6 +125,7 @@ func NewPbTransactionParserFromTransaction(pbtx *pb.Transaction, pbtxMeta *pb.Tr
 				ProgramIDIndex: uint16(instr.GetProgramIdIndex()),
 				Accounts:       convertToUint16(instr.Accounts),
 				Data:           instr.Data,
+				StackHeight:    uint16(instr.GetStackHeight()),
 			}
 		}
 	}
@@ -128,6 +138,9 @@ func NewPbTransactionParserFromTransaction(pbtx *pb.Transaction, pbtxMeta *pb.Tr
 				Decimals: uint8(tokenBalance.GetUiTokenAmount().GetDecimals()),
 			},
 		}
+		if owner, err := solana.PublicKeyFromBase58(tokenBalance.GetOwner()); err == nil {
+			txMeta.PostTokenBalances[i].Owner = &owner
+		}
 	}
 
 	for i, tokenBalance := range pbtxMeta.PreTokenBalances {
@@ -139,6 +152,9 @@ func NewPbTransactionParserFromTransaction(pbtx *pb.Transaction, pbtxMeta *pb.Tr
 				Decimals: uint8(tokenBalance.GetUiTokenAmount().GetDecimals()),
 			},
 		}
+		if owner, err := solana.PublicKeyFromBase58(tokenBalance.GetOwner()); err == nil {
+			txMeta.PreTokenBalances[i].Owner = &owner
+		}
 	}
 
 	return NewTransactionParserFromTransaction(tx, txMeta)
@@ -239,6 +255,8 @@ func NewTransactionParserFromTransaction(tx *solana.Transaction, txMeta *rpc.Tra
 			parser.SwapType = METEORA_DBC
 		} else if v.Equals(RAYDIUM_Launchpad_PROGRAM_ID) {
 			parser.SwapType = RAYDIUM_Launchpad
+		} else if v.Equals(MOONSHOT_PROGRAM_ID) {
+			parser.SwapType = MOONSHOT
 		}
 	}
 
@@ -253,46 +271,14 @@ func NewTransactionParserFromTransaction(tx *solana.Transaction, txMeta *rpc.Tra
 	return parser, nil
 }
 
+// ParseTransactionForMint returns the first pump.fun token created by the transaction, made with create or
+// create_v2 by an outer instruction or through CPI, with the dev buy of the same transaction when there is one
 func (p *Parser) ParseTransactionForMint() (*PumpfunCreateEvent, error) {
-
-	for i, outerInstruction := range p.txInfo.Message.Instructions {
-		progID := p.allAccountKeys[outerInstruction.ProgramIDIndex]
-		if progID.Equals(PUMP_FUN_PROGRAM_ID) && len(outerInstruction.Accounts) == 14 {
-
-			innerInstructions := p.getInnerInstructions(i)
-			for _, inner := range innerInstructions {
-				if p.isPumpfunCreateEventInstruction(inner) {
-					createEvent, err := p.parsePumpfunCreateEventInstruction(inner)
-					if err != nil {
-						return nil, fmt.Errorf("error processing Pumpfun create event: %s", err)
-					}
-					if createEvent != nil {
-						return createEvent, nil
-					}
-				}
-			}
-
-		}
+	launches := p.getPumpfunLaunches()
+	if len(launches) == 0 {
+		return nil, fmt.Errorf("no valid mint data found")
 	}
-	return nil, fmt.Errorf("no valid mint data found")
-}
-
-type MigrateInfo struct {
-	BaseMint  string
-	QuoteMint string
-	PoolData  interface{}
-}
-
-func (p *Parser) ParseTransactionForMigrate() (*MigrateInfo, error) {
-	if p.allAccountKeys.Contains(RAYDIUM_Launchpad_Migration_PROGRAM_ID) {
-		p.SwapType = "RAYDIUM_CPMM"
-	}
-
-	return &MigrateInfo{
-		BaseMint:  p.allAccountKeys[0].String(),
-		QuoteMint: p.allAccountKeys[0].String(),
-		PoolData:  p.allAccountKeys[0].String(),
-	}, nil
+	return launches[0], nil
 }
 
 type SwapData struct {
@@ -303,30 +289,44 @@ type SwapData struct {
 func (p *Parser) ParseTransactionForSwap() ([]SwapData, error) {
 	var parsedSwaps []SwapData
 
+	catalog := CurrentProgramCatalog()
 	skip := false
+	routed := make(map[int]bool)
 	for i, outerInstruction := range p.txInfo.Message.Instructions {
 		progID := p.allAccountKeys[outerInstruction.ProgramIDIndex]
+		entry := catalog.Program(progID)
 		switch {
-		case progID.Equals(JUPITER_PROGRAM_ID):
+		case progID.Equals(JUPITER_DCA_PROGRAM_ID):
+			skip = true
+			parsedSwaps = append(parsedSwaps, p.processDCASwaps(i)...)
+			if p.innerContainsProgram(i, JUPITER_PROGRAM_ID) {
+				parsedSwaps = append(parsedSwaps, p.processJupiterSwaps(i)...)
+			}
+		case isLimitOrderProgram(progID) ||
+			p.innerContainsProgram(i, JUPITER_LIMIT_ORDER_PROGRAM_ID) ||
+			p.innerContainsProgram(i, JUPITER_LIMIT_ORDER_V2_PROGRAM_ID):
+			skip = true
+			parsedSwaps = append(parsedSwaps, p.processLimitOrderSwaps(i)...)
+			if p.innerContainsProgram(i, JUPITER_PROGRAM_ID) {
+				parsedSwaps = append(parsedSwaps, p.processJupiterSwaps(i)...)
+			}
+		case progID.Equals(JUPITER_PROGRAM_ID) || p.innerContainsProgram(i, JUPITER_PROGRAM_ID):
 			skip = true
 			parsedSwaps = append(parsedSwaps, p.processJupiterSwaps(i)...)
 		case progID.Equals(MOONSHOT_PROGRAM_ID):
 			skip = true
-			parsedSwaps = append(parsedSwaps, p.processMoonshotSwaps()...)
-		case progID.Equals(BANANA_GUN_PROGRAM_ID) ||
-			progID.Equals(MINTECH_PROGRAM_ID) ||
-			progID.Equals(BLOOM_PROGRAM_ID) ||
-			progID.Equals(NOVA_PROGRAM_ID) ||
-			progID.Equals(MAESTRO_PROGRAM_ID):
+			parsedSwaps = append(parsedSwaps, p.processMoonshotSwaps(i)...)
+		case entry != nil && entry.Strategy == STRATEGY_INNER_INSTRUCTION_ROUTER:
 			if innerSwaps := p.processRouterSwaps(i); len(innerSwaps) > 0 {
 				parsedSwaps = append(parsedSwaps, innerSwaps...)
+				routed[i] = true
 			}
-		case progID.Equals(OKX_DEX_ROUTER_PROGRAM_ID):
+		case isOKXRouterProgram(progID) || p.innerContainsOKXRouter(i):
 			skip = true
 			parsedSwaps = append(parsedSwaps, p.processOKXSwaps(i)...)
-		case progID.Equals(AXIOM_PROGRAM_ID) || progID.Equals(AXIOM_PROGRAM_ID2):
+		case entry != nil && entry.Strategy == STRATEGY_TRANSFER_BASED:
 			skip = true
-			parsedSwaps = append(parsedSwaps, p.processAxionSwaps(i)...)
+			parsedSwaps = append(parsedSwaps, p.processTransferSwaps(i, entry.swapType())...)
 		case progID.Equals(METEORA_PROGRAM_ID) || progID.Equals(METEORA_POOLS_PROGRAM_ID) || progID.Equals(METEORA_DBC_PROGRAM_ID):
 			skip = true
 			parsedSwaps = append(parsedSwaps, p.processMeteoraSwaps(i)...)
@@ -337,6 +337,10 @@ func (p *Parser) ParseTransactionForSwap() ([]SwapData, error) {
 	}
 
 	for i, outerInstruction := range p.txInfo.Message.Instructions {
+		if routed[i] {
+			// the venue swaps of the router are parsed, its other transfers are fees and rent
+			continue
+		}
 		progID := p.allAccountKeys[outerInstruction.ProgramIDIndex]
 		switch {
 		case progID.Equals(RAYDIUM_V4_PROGRAM_ID) ||
@@ -354,6 +358,16 @@ func (p *Parser) ParseTransactionForSwap() ([]SwapData, error) {
 			parsedSwaps = append(parsedSwaps, p.processPumpfunSwaps(i)...)
 		case progID.Equals(PUMP_AMM_PROGRAM_ID):
 			parsedSwaps = append(parsedSwaps, p.processPumpAmmSwaps(i)...) // New handler for PumpSwap
+		case progID.Equals(PHOENIX_PROGRAM_ID):
+			parsedSwaps = append(parsedSwaps, p.processPhoenixSwaps(i)...)
+		case progID.Equals(OPENBOOK_V2_PROGRAM_ID):
+			parsedSwaps = append(parsedSwaps, p.processOpenbookSwaps(i)...)
+		case isPropAmmProgram(progID):
+			parsedSwaps = append(parsedSwaps, p.processPropAmmSwaps(i)...)
+		case isSanctumProgram(progID):
+			parsedSwaps = append(parsedSwaps, p.processSanctumSwaps(i)...)
+		case isStableSwapProgram(progID):
+			parsedSwaps = append(parsedSwaps, p.processStableSwaps(i)...)
 		default:
 			// progID.Equals(solana.MustPublicKeyFromBase58("HgoHJy31rnpmm99CaoKn72g1QDLf6A8vzqEKAXCyBFv5")) ||
 			// progID.Equals(solana.MustPublicKeyFromBase58("9RR5ZCvUU6rSEtE6iE4xQE4NeP9NMkbsfSsiEHCupj4M")) ||
@@ -372,6 +386,83 @@ type PoolData struct {
 	Data     interface{}
 }
 
+type RouteData struct {
+	RouteType string
+	Data      interface{}
+}
+
+// SwapLeg is a single hop of a swap executed by one venue
+type SwapLeg struct {
+	AMM              string
+	ProgramID        solana.PublicKey
+	Pool             solana.PublicKey
+	TokenInMint      solana.PublicKey
+	TokenInAmount    uint64
+	TokenInDecimals  uint8
+	TokenOutMint     solana.PublicKey
+	TokenOutAmount   uint64
+	TokenOutDecimals uint8
+	Data             interface{}
+}
+
+// legData is implemented by decoded venue swaps that map onto a single swap leg
+type legData interface {
+	swapLeg() SwapLeg
+	swapFees() []SwapFee
+	timestamp() int64
+}
+
+// routeAmounts sums the legs spending the route input before any leg produced it, and the legs
+// producing the route output that no later leg spends, so split routes report their totals
+func routeAmounts(legs []SwapLeg) (amountIn uint64, amountOut uint64) {
+	first, last := legs[0], legs[len(legs)-1]
+	produced := false
+	for _, leg := range legs {
+		if leg.TokenInMint.Equals(first.TokenInMint) && !produced {
+			amountIn += leg.TokenInAmount
+		}
+		if leg.TokenOutMint.Equals(first.TokenInMint) {
+			produced = true
+		}
+	}
+	spent := false
+	for i := len(legs) - 1; i >= 0; i-- {
+		if legs[i].TokenOutMint.Equals(last.TokenOutMint) && !spent {
+			amountOut += legs[i].TokenOutAmount
+		}
+		if legs[i].TokenInMint.Equals(last.TokenOutMint) {
+			spent = true
+		}
+	}
+	return amountIn, amountOut
+}
+
+// transferSwaps expresses the leg as the input and output transfers of the transfer based aggregation
+func (l SwapLeg) transferSwaps(swapType SwapType) []SwapData {
+	return []SwapData{
+		{Type: swapType, Data: &TransferData{
+			Info:     TransferInfo{Amount: l.TokenInAmount},
+			Type:     "transfer",
+			Mint:     l.TokenInMint.String(),
+			Decimals: l.TokenInDecimals,
+		}},
+		{Type: swapType, Data: &TransferData{
+			Info:     TransferInfo{Amount: l.TokenOutAmount},
+			Type:     "transfer",
+			Mint:     l.TokenOutMint.String(),
+			Decimals: l.TokenOutDecimals,
+		}},
+	}
+}
+
+// SwapFee is a fee paid as part of the swap, Type tells who charged it
+type SwapFee struct {
+	Type      string
+	Mint      solana.PublicKey
+	Amount    uint64
+	Recipient solana.PublicKey
+}
+
 type SwapInfo struct {
 	Signers          []solana.PublicKey
 	Signatures       []solana.Signature
@@ -379,15 +470,28 @@ type SwapInfo struct {
 	Timestamp        time.Time
 	SwapType         string
 	PoolData         *PoolData
+	RouteData        *RouteData
 	TokenInMint      solana.PublicKey
 	TokenInAmount    uint64
 	TokenInDecimals  uint8
 	TokenOutMint     solana.PublicKey
 	TokenOutAmount   uint64
 	TokenOutDecimals uint8
+	Legs             []SwapLeg
+	Fees             []SwapFee
+	Frontend         string // trading bot the swap was sent through, empty for direct swaps
 }
 
 func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
+	swapInfo, err := p.processSwapData(swapDatas)
+	if err != nil {
+		return nil, err
+	}
+	p.attributeFrontend(swapInfo)
+	return swapInfo, nil
+}
+
+func (p *Parser) processSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 	if len(swapDatas) == 0 {
 		return nil, fmt.Errorf("no swap data provided")
 	}
@@ -434,6 +538,12 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 				pumpFunPool.VirtualTokenReserves = event.VirtualTokenReserves
 				pumpFunPool.RealSOLReserves = event.RealSOLReserves
 				pumpFunPool.RealTokenReserves = event.RealTokenReserves
+				pumpFunPool.Complete = p.hasPumpfunCompleteEvent(pumpFunPool.Mint)
+				if !event.Creator.IsZero() {
+					// sell puts the creator vault at another index than buy, derive it instead
+					pumpFunPool.Creator = event.Creator
+					pumpFunPool.CreatorVault = PumpfunCreatorVault(event.Creator)
+				}
 				swapInfo.PoolData = &PoolData{
 					PoolType: string(PUMP_FUN),
 					Data:     pumpFunPool,
@@ -445,8 +555,7 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 		if pumpAmmPool != nil {
 			event := p.getPumpAmmEvent()
 			if event != nil {
-				pumpAmmPool.PoolBaseTokenReserves = event.PoolBaseTokenReserves
-				pumpAmmPool.PoolQuoteTokenReserves = event.PoolQuoteTokenReserves
+				pumpAmmPool.PoolBaseTokenReserves, pumpAmmPool.PoolQuoteTokenReserves = event.PoolReserves()
 
 				swapInfo.PoolData = &PoolData{
 					PoolType: string(PUMP_SWAP),
@@ -466,7 +575,12 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 	jupiterSwaps := make([]SwapData, 0)
 	pumpfunSwaps := make([]SwapData, 0)
 	pumpAmmSwaps := make([]SwapData, 0) // newly added
+	moonshotSwaps := make([]SwapData, 0)
+	dcaFills := make([]SwapData, 0)
+	limitOrderFills := make([]SwapData, 0)
+	legSwaps := make([]SwapData, 0)
 	otherSwaps := make([]SwapData, 0)
+	plainTransfers := 0
 
 	for _, swapData := range swapDatas {
 		switch swapData.Type {
@@ -476,9 +590,88 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 			pumpfunSwaps = append(pumpfunSwaps, swapData)
 		case PUMP_SWAP:
 			pumpAmmSwaps = append(pumpAmmSwaps, swapData)
+		case MOONSHOT:
+			moonshotSwaps = append(moonshotSwaps, swapData)
+		case JUPITER_DCA:
+			dcaFills = append(dcaFills, swapData)
+		case JUPITER_LIMIT_ORDER:
+			limitOrderFills = append(limitOrderFills, swapData)
 		default:
+			if route, ok := swapData.Data.(*OKXSwap); ok {
+				swapInfo.RouteData = &RouteData{RouteType: string(OKX), Data: route}
+				swapInfo.Fees = append(swapInfo.Fees, route.Fees...)
+				continue
+			}
+			if leg, ok := swapData.Data.(legData); ok {
+				legSwaps = append(legSwaps, swapData)
+				// keep the leg in the transfer based aggregation for routes mixing venues
+				otherSwaps = append(otherSwaps, leg.swapLeg().transferSwaps(swapData.Type)...)
+				continue
+			}
 			otherSwaps = append(otherSwaps, swapData)
+			plainTransfers++
+		}
+	}
+
+	if len(dcaFills) > 0 {
+		// keeper executed fill, the trade belongs to the DCA owner
+		fill := dcaFills[0].Data.(*DCAFilledEvent)
+		swapInfo.Signers = []solana.PublicKey{fill.UserKey}
+		swapInfo.TokenInMint = fill.InputMint
+		swapInfo.TokenInAmount = fill.InAmount
+		swapInfo.TokenInDecimals = p.splDecimalsMap[fill.InputMint.String()]
+		swapInfo.TokenOutMint = fill.OutputMint
+		swapInfo.TokenOutAmount = fill.OutAmount
+		swapInfo.TokenOutDecimals = p.splDecimalsMap[fill.OutputMint.String()]
+		swapInfo.AMMs = []string{string(JUPITER_DCA)}
+		if len(jupiterSwaps) > 0 {
+			if jupiterInfo, err := parseJupiterEvents(jupiterSwaps); err == nil {
+				swapInfo.AMMs = append(swapInfo.AMMs, jupiterInfo.AMMs...)
+				swapInfo.Legs = jupiterInfo.Legs
+				swapInfo.Fees = jupiterInfo.Fees
+			}
+		} else {
+			for _, swapData := range legSwaps {
+				swapInfo.Legs = append(swapInfo.Legs, swapData.Data.(legData).swapLeg())
+			}
 		}
+		if fill.Fee > 0 {
+			swapInfo.Fees = append(swapInfo.Fees, SwapFee{Type: "dca", Mint: fill.FeeMint, Amount: fill.Fee})
+		}
+		swapInfo.RouteData = &RouteData{
+			RouteType: string(JUPITER_DCA),
+			Data:      fill,
+		}
+		swapInfo.Timestamp = time.Now()
+		return swapInfo, nil
+	}
+
+	if len(limitOrderFills) > 0 {
+		// keeper executed fill, the trade belongs to the order maker
+		fill := limitOrderFills[0].Data.(*LimitOrderFill)
+		if !fill.Maker.IsZero() {
+			swapInfo.Signers = []solana.PublicKey{fill.Maker}
+		}
+		swapInfo.TokenInMint = fill.InputMint
+		swapInfo.TokenInAmount = fill.MakingAmount
+		swapInfo.TokenInDecimals = fill.InputDecimals
+		swapInfo.TokenOutMint = fill.OutputMint
+		swapInfo.TokenOutAmount = fill.TakingAmount
+		swapInfo.TokenOutDecimals = fill.OutputDecimals
+		swapInfo.AMMs = []string{string(JUPITER_LIMIT_ORDER)}
+		if len(jupiterSwaps) > 0 {
+			if jupiterInfo, err := parseJupiterEvents(jupiterSwaps); err == nil {
+				swapInfo.AMMs = append(swapInfo.AMMs, jupiterInfo.AMMs...)
+				swapInfo.Legs = jupiterInfo.Legs
+			}
+		}
+		swapInfo.Fees = append(swapInfo.Fees, fill.Fees...)
+		swapInfo.RouteData = &RouteData{
+			RouteType: string(JUPITER_LIMIT_ORDER),
+			Data:      fill,
+		}
+		swapInfo.Timestamp = time.Now()
+		return swapInfo, nil
 	}
 
 	if len(jupiterSwaps) > 0 {
@@ -494,10 +687,65 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 		swapInfo.TokenOutAmount = jupiterInfo.TokenOutAmount
 		swapInfo.TokenOutDecimals = jupiterInfo.TokenOutDecimals
 		swapInfo.AMMs = jupiterInfo.AMMs
+		swapInfo.Legs = jupiterInfo.Legs
+		swapInfo.Fees = jupiterInfo.Fees
+		swapInfo.RouteData = jupiterInfo.RouteData
+
+		// attach the venue detail to the matching route legs
+		used := make([]bool, len(legSwaps))
+		for i := range swapInfo.Legs {
+			for k, swapData := range legSwaps {
+				leg := swapData.Data.(legData)
+				detail := leg.swapLeg()
+				if used[k] || !jupiterLegMatches(swapInfo.Legs[i], detail) {
+					continue
+				}
+				if swapInfo.Legs[i].ProgramID.IsZero() {
+					// SwapsEvent hops do not name the amm
+					swapInfo.Legs[i].AMM = detail.AMM
+					swapInfo.Legs[i].ProgramID = detail.ProgramID
+				}
+				swapInfo.Legs[i].Pool = detail.Pool
+				swapInfo.Legs[i].Data = detail.Data
+				swapInfo.Fees = append(swapInfo.Fees, leg.swapFees()...)
+				used[k] = true
+				break
+			}
+		}
 
 		return swapInfo, nil
 	}
 
+	if len(legSwaps) > 0 && plainTransfers == 0 {
+		var timestamp int64
+		for _, swapData := range legSwaps {
+			leg := swapData.Data.(legData)
+			swapInfo.Legs = append(swapInfo.Legs, leg.swapLeg())
+			swapInfo.Fees = append(swapInfo.Fees, leg.swapFees()...)
+			if timestamp == 0 {
+				timestamp = leg.timestamp()
+			}
+		}
+		first, last := swapInfo.Legs[0], swapInfo.Legs[len(swapInfo.Legs)-1]
+		swapInfo.TokenInMint = first.TokenInMint
+		swapInfo.TokenInDecimals = first.TokenInDecimals
+		swapInfo.TokenOutMint = last.TokenOutMint
+		swapInfo.TokenOutDecimals = last.TokenOutDecimals
+		swapInfo.TokenInAmount, swapInfo.TokenOutAmount = routeAmounts(swapInfo.Legs)
+		seenAMMs := make(map[string]bool)
+		for _, leg := range swapInfo.Legs {
+			if !seenAMMs[leg.AMM] {
+				swapInfo.AMMs = append(swapInfo.AMMs, leg.AMM)
+				seenAMMs[leg.AMM] = true
+			}
+		}
+		swapInfo.Timestamp = time.Now()
+		if timestamp > 0 {
+			swapInfo.Timestamp = time.Unix(timestamp, 0)
+		}
+		return swapInfo, nil
+	}
+
 	if len(pumpfunSwaps) > 0 {
 		event := pumpfunSwaps[0].Data.(*PumpfunTradeEvent)
 		if event.IsBuy {
@@ -516,10 +764,42 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 			swapInfo.TokenOutDecimals = 9
 		}
 		swapInfo.AMMs = append(swapInfo.AMMs, string(pumpfunSwaps[0].Type))
+		swapInfo.Fees = append(swapInfo.Fees, event.swapFees()...)
 		swapInfo.Timestamp = time.Unix(int64(event.Timestamp), 0)
 		return swapInfo, nil
 	}
 
+	if len(moonshotSwaps) > 0 {
+		trade := moonshotSwaps[0].Data.(*MoonshotTradeInstructionWithMint)
+		if trade.TradeType == TradeTypeBuy {
+			swapInfo.TokenInMint = NATIVE_SOL_MINT_PROGRAM_ID
+			swapInfo.TokenInAmount = trade.CollateralAmount
+			swapInfo.TokenInDecimals = 9
+			swapInfo.TokenOutMint = trade.Mint
+			swapInfo.TokenOutAmount = trade.TokenAmount
+			swapInfo.TokenOutDecimals = p.splDecimalsMap[trade.Mint.String()]
+		} else {
+			swapInfo.TokenInMint = trade.Mint
+			swapInfo.TokenInAmount = trade.TokenAmount
+			swapInfo.TokenInDecimals = p.splDecimalsMap[trade.Mint.String()]
+			swapInfo.TokenOutMint = NATIVE_SOL_MINT_PROGRAM_ID
+			swapInfo.TokenOutAmount = trade.CollateralAmount
+			swapInfo.TokenOutDecimals = 9
+		}
+		if !trade.Sender.IsZero() {
+			swapInfo.Signers = []solana.PublicKey{trade.Sender}
+		}
+		if moonshotPool := p.getMoonshotPool(trade); moonshotPool != nil {
+			swapInfo.PoolData = &PoolData{
+				PoolType: string(MOONSHOT),
+				Data:     moonshotPool,
+			}
+		}
+		swapInfo.AMMs = append(swapInfo.AMMs, string(MOONSHOT))
+		swapInfo.Timestamp = time.Now()
+		return swapInfo, nil
+	}
+
 	//newly added
 	if len(pumpAmmSwaps) > 0 {
 		inputAmounts := make(map[string]uint64)
@@ -544,6 +824,14 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 			delete(inputAmounts, NATIVE_SOL_MINT_PROGRAM_ID.String())
 		}
 
+		// routed swaps move the tokens with the router as authority, the owner's accounts give the direction
+		if input, output, amountIn, amountOut, ok := p.ownerTransferDirection(swapInfo.Signers[0], pumpAmmSwaps); ok {
+			inputAmounts = map[string]uint64{input.mint: amountIn}
+			inputDecimals[input.mint] = input.decimals
+			outputAmounts = map[string]uint64{output.mint: amountOut}
+			outputDecimals[output.mint] = output.decimals
+		}
+
 		if len(inputAmounts) == 1 && len(outputAmounts) == 1 {
 			for mint, amount := range inputAmounts {
 				swapInfo.TokenInMint = solana.MustPublicKeyFromBase58(mint)
@@ -585,7 +873,6 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 			var totalInputAmount uint64 = 0
 			var totalOutputAmount uint64 = 0
 
-			swapChange := false
 			for _, swapData := range otherSwaps {
 				transfer := getTransferFromSwapData(swapData)
 				if transfer == nil {
@@ -601,16 +888,12 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 					totalOutputAmount += transfer.amount
 					seenOutputs[amountStr] = true
 				}
-
-				if swapData.Type == AXION {
-					swapChange = true
-				}
 			}
 
-			//inputTransfer和outputTransfer 置换
-			if swapChange {
-				inputTransfer, outputTransfer = outputTransfer, inputTransfer
-				totalInputAmount, totalOutputAmount = totalOutputAmount, totalInputAmount
+			// routers do not order their transfers, the direction comes from what the swap owner spent and received
+			if input, output, amountIn, amountOut, ok := p.ownerTransferDirection(swapInfo.Signers[0], otherSwaps); ok {
+				inputTransfer, outputTransfer = *input, *output
+				totalInputAmount, totalOutputAmount = amountIn, amountOut
 			}
 
 			swapInfo.TokenInMint = solana.MustPublicKeyFromBase58(inputTransfer.mint)
@@ -627,6 +910,11 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 					seenAMMs[string(swapData.Type)] = true
 				}
 			}
+			for _, swapData := range legSwaps {
+				leg := swapData.Data.(legData)
+				swapInfo.Legs = append(swapInfo.Legs, leg.swapLeg())
+				swapInfo.Fees = append(swapInfo.Fees, leg.swapFees()...)
+			}
 
 			swapInfo.Timestamp = time.Now()
 			return swapInfo, nil
@@ -640,33 +928,97 @@ func getTransferFromSwapData(swapData SwapData) *TokenTransfer {
 	switch data := swapData.Data.(type) {
 	case *SystemTransfer:
 		return &TokenTransfer{
-			user:     data.From,
-			mint:     NATIVE_SOL_MINT_PROGRAM_ID.String(),
-			amount:   data.Amount,
-			decimals: 9,
+			user:        data.From,
+			source:      data.From,
+			destination: data.To,
+			mint:        NATIVE_SOL_MINT_PROGRAM_ID.String(),
+			amount:      data.Amount,
+			decimals:    9,
 		}
 	case *TransferData:
 		return &TokenTransfer{
-			user:     data.Info.Source,
-			mint:     data.Mint,
-			amount:   data.Info.Amount,
-			decimals: data.Decimals,
+			user:        data.Info.Authority,
+			source:      data.Info.Source,
+			destination: data.Info.Destination,
+			mint:        data.Mint,
+			amount:      data.Info.Amount,
+			decimals:    data.Decimals,
 		}
+	case *InputTransfer:
+		return getTransferFromSwapData(SwapData{Data: &data.TransferData})
+	case *OutputTransfer:
+		return getTransferFromSwapData(SwapData{Data: &data.TransferData})
 	case *TransferCheck:
 		amt, err := strconv.ParseUint(data.Info.TokenAmount.Amount, 10, 64)
 		if err != nil {
 			return nil
 		}
 		return &TokenTransfer{
-			user:     data.Info.Source,
-			mint:     data.Info.Mint,
-			amount:   amt,
-			decimals: data.Info.TokenAmount.Decimals,
+			user:        data.Info.Authority,
+			source:      data.Info.Source,
+			destination: data.Info.Destination,
+			mint:        data.Info.Mint,
+			amount:      amt,
+			decimals:    data.Info.TokenAmount.Decimals,
 		}
 	}
 	return nil
 }
 
+// ownerTransferDirection nets the transfers leaving and reaching the accounts of owner per mint, the input
+// is the mint the owner spent most of and the output the mint it received most of
+func (p *Parser) ownerTransferDirection(owner solana.PublicKey, swapDatas []SwapData) (input, output *TokenTransfer, amountIn, amountOut uint64, ok bool) {
+	sent, received := make(map[string]uint64), make(map[string]uint64)
+	transfers := make(map[string]*TokenTransfer)
+	seen := make(map[TokenTransfer]bool)
+	for _, swapData := range swapDatas {
+		transfer := getTransferFromSwapData(swapData)
+		if transfer == nil || seen[*transfer] {
+			continue
+		}
+		seen[*transfer] = true
+
+		fromOwner := transfer.user == owner.String() || p.accountOwner(transfer.source).Equals(owner)
+		toOwner := p.accountOwner(transfer.destination).Equals(owner)
+		switch {
+		case fromOwner && !toOwner:
+			sent[transfer.mint] += transfer.amount
+		case toOwner && !fromOwner:
+			received[transfer.mint] += transfer.amount
+		default:
+			continue
+		}
+		if transfers[transfer.mint] == nil {
+			transfers[transfer.mint] = transfer
+		}
+	}
+
+	for mint := range transfers {
+		switch {
+		case sent[mint] > received[mint] && sent[mint]-received[mint] > amountIn:
+			input, amountIn = transfers[mint], sent[mint]-received[mint]
+		case received[mint] > sent[mint] && received[mint]-sent[mint] > amountOut:
+			output, amountOut = transfers[mint], received[mint]-sent[mint]
+		}
+	}
+	if input == nil || output == nil {
+		return nil, nil, 0, 0, false
+	}
+	return input, output, amountIn, amountOut, true
+}
+
+// accountOwner returns the owner of a token account, or the account itself for wallets
+func (p *Parser) accountOwner(account string) solana.PublicKey {
+	key, err := solana.PublicKeyFromBase58(account)
+	if err != nil {
+		return solana.PublicKey{}
+	}
+	if owner := p.tokenAccountOwner(key); !owner.IsZero() {
+		return owner
+	}
+	return key
+}
+
 func (p *Parser) processRouterSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
 
@@ -686,23 +1038,61 @@ func (p *Parser) processRouterSwaps(instructionIndex int) []SwapData {
 			progID.Equals(RAYDIUM_AMM_PROGRAM_ID) ||
 			progID.Equals(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID)) && !processedProtocols[PROTOCOL_RAYDIUM]:
 			processedProtocols[PROTOCOL_RAYDIUM] = true
-			if raydSwaps := p.processRaydSwaps(instructionIndex); len(raydSwaps) > 0 {
+			if raydSwaps := p.processVenueTransfers(instructionIndex, RAYDIUM, p.processRaydSwaps,
+				RAYDIUM_V4_PROGRAM_ID, RAYDIUM_CPMM_PROGRAM_ID, RAYDIUM_AMM_PROGRAM_ID, RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID); len(raydSwaps) > 0 {
 				swaps = append(swaps, raydSwaps...)
 			}
 
 		case progID.Equals(ORCA_PROGRAM_ID) && !processedProtocols[PROTOCOL_ORCA]:
 			processedProtocols[PROTOCOL_ORCA] = true
-			if orcaSwaps := p.processOrcaSwaps(instructionIndex); len(orcaSwaps) > 0 {
+			if orcaSwaps := p.processVenueTransfers(instructionIndex, ORCA, p.processOrcaSwaps, ORCA_PROGRAM_ID); len(orcaSwaps) > 0 {
 				swaps = append(swaps, orcaSwaps...)
 			}
 
 		case (progID.Equals(METEORA_PROGRAM_ID) ||
 			progID.Equals(METEORA_POOLS_PROGRAM_ID) || progID.Equals(METEORA_DBC_PROGRAM_ID)) && !processedProtocols[PROTOCOL_METEORA]:
 			processedProtocols[PROTOCOL_METEORA] = true
-			if meteoraSwaps := p.processMeteoraSwaps(instructionIndex); len(meteoraSwaps) > 0 {
+			if meteoraSwaps := p.processVenueTransfers(instructionIndex, METEORA, p.processMeteoraSwaps,
+				METEORA_PROGRAM_ID, METEORA_POOLS_PROGRAM_ID, METEORA_DBC_PROGRAM_ID, METEORA_VAULT_PROGRAM_ID); len(meteoraSwaps) > 0 {
 				swaps = append(swaps, meteoraSwaps...)
 			}
 
+		case progID.Equals(PHOENIX_PROGRAM_ID) && !processedProtocols[PROTOCOL_PHOENIX]:
+			processedProtocols[PROTOCOL_PHOENIX] = true
+			if phoenixSwaps := p.processPhoenixSwaps(instructionIndex); len(phoenixSwaps) > 0 {
+				swaps = append(swaps, phoenixSwaps...)
+			}
+
+		case progID.Equals(OPENBOOK_V2_PROGRAM_ID) && !processedProtocols[PROTOCOL_OPENBOOK]:
+			processedProtocols[PROTOCOL_OPENBOOK] = true
+			if openbookSwaps := p.processOpenbookSwaps(instructionIndex); len(openbookSwaps) > 0 {
+				swaps = append(swaps, openbookSwaps...)
+			}
+
+		case isPropAmmProgram(progID) && !processedProtocols[PROTOCOL_PROP_AMM]:
+			processedProtocols[PROTOCOL_PROP_AMM] = true
+			if propAmmSwaps := p.processPropAmmSwaps(instructionIndex); len(propAmmSwaps) > 0 {
+				swaps = append(swaps, propAmmSwaps...)
+			}
+
+		case isStableSwapProgram(progID) && !processedProtocols[PROTOCOL_STABLE]:
+			processedProtocols[PROTOCOL_STABLE] = true
+			if stableSwaps := p.processStableSwaps(instructionIndex); len(stableSwaps) > 0 {
+				swaps = append(swaps, stableSwaps...)
+			}
+
+		case isSanctumProgram(progID) && !processedProtocols[PROTOCOL_SANCTUM]:
+			processedProtocols[PROTOCOL_SANCTUM] = true
+			if sanctumSwaps := p.processSanctumSwaps(instructionIndex); len(sanctumSwaps) > 0 {
+				swaps = append(swaps, sanctumSwaps...)
+			}
+
+		case progID.Equals(MOONSHOT_PROGRAM_ID) && !processedProtocols[PROTOCOL_MOONSHOT]:
+			processedProtocols[PROTOCOL_MOONSHOT] = true
+			if moonshotSwaps := p.processMoonshotSwaps(instructionIndex); len(moonshotSwaps) > 0 {
+				swaps = append(swaps, moonshotSwaps...)
+			}
+
 		case (progID.Equals(PUMP_FUN_PROGRAM_ID) ||
 			progID.Equals(solana.MustPublicKeyFromBase58("BSfD6SHZigAfDWSjzD5Q41jw8LmKwtmjskPH9XW1mrRW"))) && !processedProtocols[PROTOCOL_PUMPFUN]:
 			processedProtocols[PROTOCOL_PUMPFUN] = true
@@ -0,0 +1,133 @@
+// Code generated by idlgen from idls/pump_amm.json. DO NOT EDIT.
+
+package solanaswapgo
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+// PumpAmmBuyDiscriminator prefixes the data of the buy instruction
+var PumpAmmBuyDiscriminator = [8]byte{102, 6, 61, 18, 1, 218, 235, 234}
+
+// PumpAmmBuyArgs are the arguments of the buy instruction
+type PumpAmmBuyArgs struct {
+	BaseAmountOut    uint64
+	MaxQuoteAmountIn uint64
+}
+
+// PumpAmmBuyAccounts are the accounts of the buy instruction
+type PumpAmmBuyAccounts struct {
+	Pool                             solana.PublicKey
+	User                             solana.PublicKey
+	GlobalConfig                     solana.PublicKey
+	BaseMint                         solana.PublicKey
+	QuoteMint                        solana.PublicKey
+	UserBaseTokenAccount             solana.PublicKey
+	UserQuoteTokenAccount            solana.PublicKey
+	PoolBaseTokenAccount             solana.PublicKey
+	PoolQuoteTokenAccount            solana.PublicKey
+	ProtocolFeeRecipient             solana.PublicKey
+	ProtocolFeeRecipientTokenAccount solana.PublicKey
+	BaseTokenProgram                 solana.PublicKey
+	QuoteTokenProgram                solana.PublicKey
+	SystemProgram                    solana.PublicKey
+	AssociatedTokenProgram           solana.PublicKey
+	EventAuthority                   solana.PublicKey
+	Program                          solana.PublicKey
+	CoinCreatorVaultAta              solana.PublicKey
+	CoinCreatorVaultAuthority        solana.PublicKey
+}
+
+// PumpAmmBuyAccountsLen is the number of accounts of the buy instruction
+const PumpAmmBuyAccountsLen = 19
+
+// NewPumpAmmBuyAccounts names the accounts of a buy instruction, ok is false when some are missing
+func NewPumpAmmBuyAccounts(keys []solana.PublicKey) (accounts *PumpAmmBuyAccounts, ok bool) {
+	if len(keys) < PumpAmmBuyAccountsLen {
+		return nil, false
+	}
+	return &PumpAmmBuyAccounts{
+		Pool:                             keys[0],
+		User:                             keys[1],
+		GlobalConfig:                     keys[2],
+		BaseMint:                         keys[3],
+		QuoteMint:                        keys[4],
+		UserBaseTokenAccount:             keys[5],
+		UserQuoteTokenAccount:            keys[6],
+		PoolBaseTokenAccount:             keys[7],
+		PoolQuoteTokenAccount:            keys[8],
+		ProtocolFeeRecipient:             keys[9],
+		ProtocolFeeRecipientTokenAccount: keys[10],
+		BaseTokenProgram:                 keys[11],
+		QuoteTokenProgram:                keys[12],
+		SystemProgram:                    keys[13],
+		AssociatedTokenProgram:           keys[14],
+		EventAuthority:                   keys[15],
+		Program:                          keys[16],
+		CoinCreatorVaultAta:              keys[17],
+		CoinCreatorVaultAuthority:        keys[18],
+	}, true
+}
+
+// PumpAmmSellDiscriminator prefixes the data of the sell instruction
+var PumpAmmSellDiscriminator = [8]byte{51, 230, 133, 164, 1, 127, 131, 173}
+
+// PumpAmmSellArgs are the arguments of the sell instruction
+type PumpAmmSellArgs struct {
+	BaseAmountIn      uint64
+	MinQuoteAmountOut uint64
+}
+
+// PumpAmmSellAccounts are the accounts of the sell instruction
+type PumpAmmSellAccounts struct {
+	Pool                             solana.PublicKey
+	User                             solana.PublicKey
+	GlobalConfig                     solana.PublicKey
+	BaseMint                         solana.PublicKey
+	QuoteMint                        solana.PublicKey
+	UserBaseTokenAccount             solana.PublicKey
+	UserQuoteTokenAccount            solana.PublicKey
+	PoolBaseTokenAccount             solana.PublicKey
+	PoolQuoteTokenAccount            solana.PublicKey
+	ProtocolFeeRecipient             solana.PublicKey
+	ProtocolFeeRecipientTokenAccount solana.PublicKey
+	BaseTokenProgram                 solana.PublicKey
+	QuoteTokenProgram                solana.PublicKey
+	SystemProgram                    solana.PublicKey
+	AssociatedTokenProgram           solana.PublicKey
+	EventAuthority                   solana.PublicKey
+	Program                          solana.PublicKey
+	CoinCreatorVaultAta              solana.PublicKey
+	CoinCreatorVaultAuthority        solana.PublicKey
+}
+
+// PumpAmmSellAccountsLen is the number of accounts of the sell instruction
+const PumpAmmSellAccountsLen = 19
+
+// NewPumpAmmSellAccounts names the accounts of a sell instruction, ok is false when some are missing
+func NewPumpAmmSellAccounts(keys []solana.PublicKey) (accounts *PumpAmmSellAccounts, ok bool) {
+	if len(keys) < PumpAmmSellAccountsLen {
+		return nil, false
+	}
+	return &PumpAmmSellAccounts{
+		Pool:                             keys[0],
+		User:                             keys[1],
+		GlobalConfig:                     keys[2],
+		BaseMint:                         keys[3],
+		QuoteMint:                        keys[4],
+		UserBaseTokenAccount:             keys[5],
+		UserQuoteTokenAccount:            keys[6],
+		PoolBaseTokenAccount:             keys[7],
+		PoolQuoteTokenAccount:            keys[8],
+		ProtocolFeeRecipient:             keys[9],
+		ProtocolFeeRecipientTokenAccount: keys[10],
+		BaseTokenProgram:                 keys[11],
+		QuoteTokenProgram:                keys[12],
+		SystemProgram:                    keys[13],
+		AssociatedTokenProgram:           keys[14],
+		EventAuthority:                   keys[15],
+		Program:                          keys[16],
+		CoinCreatorVaultAta:              keys[17],
+		CoinCreatorVaultAuthority:        keys[18],
+	}, true
+}
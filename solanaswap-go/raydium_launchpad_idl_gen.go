@@ -0,0 +1,255 @@
+// Code generated by idlgen from idls/raydium_launchpad.json. DO NOT EDIT.
+
+package solanaswapgo
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+// RaydiumLaunchpadBuyExactInDiscriminator prefixes the data of the buy_exact_in instruction
+var RaydiumLaunchpadBuyExactInDiscriminator = [8]byte{250, 234, 13, 123, 213, 156, 19, 236}
+
+// RaydiumLaunchpadBuyExactInArgs are the arguments of the buy_exact_in instruction
+type RaydiumLaunchpadBuyExactInArgs struct {
+	AmountIn         uint64
+	MinimumAmountOut uint64
+	ShareFeeRate     uint64
+}
+
+// RaydiumLaunchpadBuyExactInAccounts are the accounts of the buy_exact_in instruction
+type RaydiumLaunchpadBuyExactInAccounts struct {
+	Payer             solana.PublicKey
+	Authority         solana.PublicKey
+	GlobalConfig      solana.PublicKey
+	PlatformConfig    solana.PublicKey
+	PoolState         solana.PublicKey
+	UserBaseToken     solana.PublicKey
+	UserQuoteToken    solana.PublicKey
+	BaseVault         solana.PublicKey
+	QuoteVault        solana.PublicKey
+	BaseTokenMint     solana.PublicKey
+	QuoteTokenMint    solana.PublicKey
+	BaseTokenProgram  solana.PublicKey
+	QuoteTokenProgram solana.PublicKey
+	EventAuthority    solana.PublicKey
+	Program           solana.PublicKey
+	SystemProgram     solana.PublicKey
+	PlatformFeeVault  solana.PublicKey
+	CreatorFeeVault   solana.PublicKey
+}
+
+// RaydiumLaunchpadBuyExactInAccountsLen is the number of accounts of the buy_exact_in instruction
+const RaydiumLaunchpadBuyExactInAccountsLen = 18
+
+// NewRaydiumLaunchpadBuyExactInAccounts names the accounts of a buy_exact_in instruction, ok is false when some are missing
+func NewRaydiumLaunchpadBuyExactInAccounts(keys []solana.PublicKey) (accounts *RaydiumLaunchpadBuyExactInAccounts, ok bool) {
+	if len(keys) < RaydiumLaunchpadBuyExactInAccountsLen {
+		return nil, false
+	}
+	return &RaydiumLaunchpadBuyExactInAccounts{
+		Payer:             keys[0],
+		Authority:         keys[1],
+		GlobalConfig:      keys[2],
+		PlatformConfig:    keys[3],
+		PoolState:         keys[4],
+		UserBaseToken:     keys[5],
+		UserQuoteToken:    keys[6],
+		BaseVault:         keys[7],
+		QuoteVault:        keys[8],
+		BaseTokenMint:     keys[9],
+		QuoteTokenMint:    keys[10],
+		BaseTokenProgram:  keys[11],
+		QuoteTokenProgram: keys[12],
+		EventAuthority:    keys[13],
+		Program:           keys[14],
+		SystemProgram:     keys[15],
+		PlatformFeeVault:  keys[16],
+		CreatorFeeVault:   keys[17],
+	}, true
+}
+
+// RaydiumLaunchpadBuyExactOutDiscriminator prefixes the data of the buy_exact_out instruction
+var RaydiumLaunchpadBuyExactOutDiscriminator = [8]byte{24, 211, 116, 40, 105, 3, 153, 56}
+
+// RaydiumLaunchpadBuyExactOutArgs are the arguments of the buy_exact_out instruction
+type RaydiumLaunchpadBuyExactOutArgs struct {
+	AmountOut       uint64
+	MaximumAmountIn uint64
+	ShareFeeRate    uint64
+}
+
+// RaydiumLaunchpadBuyExactOutAccounts are the accounts of the buy_exact_out instruction
+type RaydiumLaunchpadBuyExactOutAccounts struct {
+	Payer             solana.PublicKey
+	Authority         solana.PublicKey
+	GlobalConfig      solana.PublicKey
+	PlatformConfig    solana.PublicKey
+	PoolState         solana.PublicKey
+	UserBaseToken     solana.PublicKey
+	UserQuoteToken    solana.PublicKey
+	BaseVault         solana.PublicKey
+	QuoteVault        solana.PublicKey
+	BaseTokenMint     solana.PublicKey
+	QuoteTokenMint    solana.PublicKey
+	BaseTokenProgram  solana.PublicKey
+	QuoteTokenProgram solana.PublicKey
+	EventAuthority    solana.PublicKey
+	Program           solana.PublicKey
+	SystemProgram     solana.PublicKey
+	PlatformFeeVault  solana.PublicKey
+	CreatorFeeVault   solana.PublicKey
+}
+
+// RaydiumLaunchpadBuyExactOutAccountsLen is the number of accounts of the buy_exact_out instruction
+const RaydiumLaunchpadBuyExactOutAccountsLen = 18
+
+// NewRaydiumLaunchpadBuyExactOutAccounts names the accounts of a buy_exact_out instruction, ok is false when some are missing
+func NewRaydiumLaunchpadBuyExactOutAccounts(keys []solana.PublicKey) (accounts *RaydiumLaunchpadBuyExactOutAccounts, ok bool) {
+	if len(keys) < RaydiumLaunchpadBuyExactOutAccountsLen {
+		return nil, false
+	}
+	return &RaydiumLaunchpadBuyExactOutAccounts{
+		Payer:             keys[0],
+		Authority:         keys[1],
+		GlobalConfig:      keys[2],
+		PlatformConfig:    keys[3],
+		PoolState:         keys[4],
+		UserBaseToken:     keys[5],
+		UserQuoteToken:    keys[6],
+		BaseVault:         keys[7],
+		QuoteVault:        keys[8],
+		BaseTokenMint:     keys[9],
+		QuoteTokenMint:    keys[10],
+		BaseTokenProgram:  keys[11],
+		QuoteTokenProgram: keys[12],
+		EventAuthority:    keys[13],
+		Program:           keys[14],
+		SystemProgram:     keys[15],
+		PlatformFeeVault:  keys[16],
+		CreatorFeeVault:   keys[17],
+	}, true
+}
+
+// RaydiumLaunchpadSellExactInDiscriminator prefixes the data of the sell_exact_in instruction
+var RaydiumLaunchpadSellExactInDiscriminator = [8]byte{149, 39, 222, 155, 211, 124, 152, 26}
+
+// RaydiumLaunchpadSellExactInArgs are the arguments of the sell_exact_in instruction
+type RaydiumLaunchpadSellExactInArgs struct {
+	AmountIn         uint64
+	MinimumAmountOut uint64
+	ShareFeeRate     uint64
+}
+
+// RaydiumLaunchpadSellExactInAccounts are the accounts of the sell_exact_in instruction
+type RaydiumLaunchpadSellExactInAccounts struct {
+	Payer             solana.PublicKey
+	Authority         solana.PublicKey
+	GlobalConfig      solana.PublicKey
+	PlatformConfig    solana.PublicKey
+	PoolState         solana.PublicKey
+	UserBaseToken     solana.PublicKey
+	UserQuoteToken    solana.PublicKey
+	BaseVault         solana.PublicKey
+	QuoteVault        solana.PublicKey
+	BaseTokenMint     solana.PublicKey
+	QuoteTokenMint    solana.PublicKey
+	BaseTokenProgram  solana.PublicKey
+	QuoteTokenProgram solana.PublicKey
+	EventAuthority    solana.PublicKey
+	Program           solana.PublicKey
+	SystemProgram     solana.PublicKey
+	PlatformFeeVault  solana.PublicKey
+	CreatorFeeVault   solana.PublicKey
+}
+
+// RaydiumLaunchpadSellExactInAccountsLen is the number of accounts of the sell_exact_in instruction
+const RaydiumLaunchpadSellExactInAccountsLen = 18
+
+// NewRaydiumLaunchpadSellExactInAccounts names the accounts of a sell_exact_in instruction, ok is false when some are missing
+func NewRaydiumLaunchpadSellExactInAccounts(keys []solana.PublicKey) (accounts *RaydiumLaunchpadSellExactInAccounts, ok bool) {
+	if len(keys) < RaydiumLaunchpadSellExactInAccountsLen {
+		return nil, false
+	}
+	return &RaydiumLaunchpadSellExactInAccounts{
+		Payer:             keys[0],
+		Authority:         keys[1],
+		GlobalConfig:      keys[2],
+		PlatformConfig:    keys[3],
+		PoolState:         keys[4],
+		UserBaseToken:     keys[5],
+		UserQuoteToken:    keys[6],
+		BaseVault:         keys[7],
+		QuoteVault:        keys[8],
+		BaseTokenMint:     keys[9],
+		QuoteTokenMint:    keys[10],
+		BaseTokenProgram:  keys[11],
+		QuoteTokenProgram: keys[12],
+		EventAuthority:    keys[13],
+		Program:           keys[14],
+		SystemProgram:     keys[15],
+		PlatformFeeVault:  keys[16],
+		CreatorFeeVault:   keys[17],
+	}, true
+}
+
+// RaydiumLaunchpadSellExactOutDiscriminator prefixes the data of the sell_exact_out instruction
+var RaydiumLaunchpadSellExactOutDiscriminator = [8]byte{95, 200, 71, 34, 8, 9, 11, 166}
+
+// RaydiumLaunchpadSellExactOutArgs are the arguments of the sell_exact_out instruction
+type RaydiumLaunchpadSellExactOutArgs struct {
+	AmountOut       uint64
+	MaximumAmountIn uint64
+	ShareFeeRate    uint64
+}
+
+// RaydiumLaunchpadSellExactOutAccounts are the accounts of the sell_exact_out instruction
+type RaydiumLaunchpadSellExactOutAccounts struct {
+	Payer             solana.PublicKey
+	Authority         solana.PublicKey
+	GlobalConfig      solana.PublicKey
+	PlatformConfig    solana.PublicKey
+	PoolState         solana.PublicKey
+	UserBaseToken     solana.PublicKey
+	UserQuoteToken    solana.PublicKey
+	BaseVault         solana.PublicKey
+	QuoteVault        solana.PublicKey
+	BaseTokenMint     solana.PublicKey
+	QuoteTokenMint    solana.PublicKey
+	BaseTokenProgram  solana.PublicKey
+	QuoteTokenProgram solana.PublicKey
+	EventAuthority    solana.PublicKey
+	Program           solana.PublicKey
+	SystemProgram     solana.PublicKey
+	PlatformFeeVault  solana.PublicKey
+	CreatorFeeVault   solana.PublicKey
+}
+
+// RaydiumLaunchpadSellExactOutAccountsLen is the number of accounts of the sell_exact_out instruction
+const RaydiumLaunchpadSellExactOutAccountsLen = 18
+
+// NewRaydiumLaunchpadSellExactOutAccounts names the accounts of a sell_exact_out instruction, ok is false when some are missing
+func NewRaydiumLaunchpadSellExactOutAccounts(keys []solana.PublicKey) (accounts *RaydiumLaunchpadSellExactOutAccounts, ok bool) {
+	if len(keys) < RaydiumLaunchpadSellExactOutAccountsLen {
+		return nil, false
+	}
+	return &RaydiumLaunchpadSellExactOutAccounts{
+		Payer:             keys[0],
+		Authority:         keys[1],
+		GlobalConfig:      keys[2],
+		PlatformConfig:    keys[3],
+		PoolState:         keys[4],
+		UserBaseToken:     keys[5],
+		UserQuoteToken:    keys[6],
+		BaseVault:         keys[7],
+		QuoteVault:        keys[8],
+		BaseTokenMint:     keys[9],
+		QuoteTokenMint:    keys[10],
+		BaseTokenProgram:  keys[11],
+		QuoteTokenProgram: keys[12],
+		EventAuthority:    keys[13],
+		Program:           keys[14],
+		SystemProgram:     keys[15],
+		PlatformFeeVault:  keys[16],
+		CreatorFeeVault:   keys[17],
+	}, true
+}
@@ -12,3 +12,50 @@ func (p *Parser) convertRPCToSolanaInstruction(rpcInst rpc.CompiledInstruction)
 		Data:           rpcInst.Data,
 	}
 }
+
+// innerContainsProgram checks if any inner instruction of the outer instruction invokes programID
+func (p *Parser) innerContainsProgram(instructionIndex int, programID solana.PublicKey) bool {
+	for _, inner := range p.getInnerInstructions(instructionIndex) {
+		if p.allAccountKeys[inner.ProgramIDIndex].Equals(programID) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenAccountOwner resolves the owner of a token account from the token balances of the transaction
+func (p *Parser) tokenAccountOwner(account solana.PublicKey) solana.PublicKey {
+	if p.txMeta == nil {
+		return solana.PublicKey{}
+	}
+	for _, balances := range [][]rpc.TokenBalance{p.txMeta.PostTokenBalances, p.txMeta.PreTokenBalances} {
+		for _, balance := range balances {
+			if int(balance.AccountIndex) < len(p.allAccountKeys) && p.allAccountKeys[balance.AccountIndex].Equals(account) && balance.Owner != nil {
+				return *balance.Owner
+			}
+		}
+	}
+	return solana.PublicKey{}
+}
+
+// instructionAccountKeys resolves the account indexes of an instruction
+func (p *Parser) instructionAccountKeys(inst solana.CompiledInstruction) []solana.PublicKey {
+	keys := make([]solana.PublicKey, 0, len(inst.Accounts))
+	for _, index := range inst.Accounts {
+		if int(index) >= len(p.allAccountKeys) {
+			return nil
+		}
+		keys = append(keys, p.allAccountKeys[index])
+	}
+	return keys
+}
+
+// forEachInstruction visits the outer instructions and their inner instructions in execution order
+func (p *Parser) forEachInstruction(visit func(inst solana.CompiledInstruction)) {
+	for i, outerInstruction := range p.txInfo.Message.Instructions {
+		visit(outerInstruction)
+		for _, inner := range p.getInnerInstructions(i) {
+			visit(p.convertRPCToSolanaInstruction(inner))
+		}
+	}
+}